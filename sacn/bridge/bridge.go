@@ -0,0 +1,236 @@
+/*
+Package bridge connects sACN to other protocols. Currently OSC (Open Sound Control) over UDP is supported.
+
+Every DMX slot is mapped to the OSC address /sacn/{universe}/{slot} with a single int32 argument in range
+[0-255]. The slots are numbered from 1 to 512, like the channels of a lighting desk.
+*/
+package bridge
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+// Bridge forwards data between sACN and OSC. Use NewSACNToOSCBridge or NewOSCToSACNBridge to create one.
+type Bridge struct {
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closed    bool //protected by mu
+
+	//sACN to OSC
+	out  *net.UDPConn
+	last map[uint16][]byte //the last frame of every universe that was forwarded
+
+	//OSC to sACN
+	tx        *sacn.Transmitter
+	mu        sync.Mutex
+	in        net.PacketConn
+	frames    map[uint16][]byte
+	activated map[uint16]chan<- []byte //the universes that were activated by the bridge
+}
+
+// NewSACNToOSCBridge creates a bridge that forwards the DMX data of all universes the receiver gets to the
+// OSC server at oscAddr. Only the slots that changed since the last frame of the universe are sent. All
+// changed slots of a frame are sent as one OSC bundle. The receiver has to be started separately.
+// Note that frames of all sources are forwarded, so if multiple sources send on the same universe, use
+// a receiver with a CID whitelist (see ReceiverSocket.SetCIDWhitelist).
+func NewSACNToOSCBridge(rx *sacn.ReceiverSocket, oscAddr string) (*Bridge, error) {
+	addr, err := net.ResolveUDPAddr("udp", oscAddr)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := rx.SubscribeAll()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bridge{
+		stop: make(chan struct{}),
+		out:  conn,
+		last: make(map[uint16][]byte),
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case <-b.stop:
+				return
+			case frame, ok := <-frames:
+				if !ok {
+					return
+				}
+				if bundle := b.frameBundle(frame.Universe, frame.Data); bundle != nil {
+					conn.Write(bundle)
+				}
+			}
+		}
+	}()
+	return b, nil
+}
+
+// frameBundle creates the OSC bundle with all slots of the frame that changed since the last frame of
+// the universe. Returns nil if nothing changed.
+func (b *Bridge) frameBundle(universe uint16, data []byte) []byte {
+	old, known := b.last[universe]
+	messages := make([][]byte, 0)
+	for i, value := range data {
+		if known && i < len(old) && old[i] == value {
+			continue
+		}
+		messages = append(messages, encodeMessage(slotAddress(universe, i+1), int32(value)))
+	}
+	b.last[universe] = append([]byte(nil), data...)
+	if len(messages) == 0 {
+		return nil
+	}
+	return encodeBundle(messages)
+}
+
+// NewOSCToSACNBridge creates a bridge that sends the slot values it receives via OSC on the transmitter.
+// Universes that are not activated on the transmitter are activated by the bridge and deactivated again
+// when the bridge is closed. Use ListenOSC to receive OSC packets via UDP or pass the packets to HandleOSC.
+func NewOSCToSACNBridge(tx *sacn.Transmitter) (*Bridge, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("the transmitter is nil")
+	}
+	return &Bridge{
+		stop:      make(chan struct{}),
+		tx:        tx,
+		frames:    make(map[uint16][]byte),
+		activated: make(map[uint16]chan<- []byte),
+	}, nil
+}
+
+// ListenOSC starts receiving OSC packets on the given UDP address, e.g. ":9000". Packets that can not be
+// parsed are dropped. Only one address can be listened on.
+func (b *Bridge) ListenOSC(addr string) error {
+	if b.tx == nil {
+		return fmt.Errorf("the bridge does not forward OSC to sACN")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf("the bridge is closed")
+	}
+	if b.in != nil {
+		return fmt.Errorf("the bridge is already listening on %v", b.in.LocalAddr())
+	}
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	b.in = conn
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		buf := make([]byte, 65535)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return //the connection was closed
+			}
+			b.HandleOSC(buf[:n])
+		}
+	}()
+	return nil
+}
+
+// HandleOSC parses the raw OSC packet and sends the slot values on the transmitter. All slots of a bundle
+// are sent together in one frame per universe. Messages with other addresses than /sacn/{universe}/{slot}
+// are ignored. Values outside of [0-255] are clamped.
+func (b *Bridge) HandleOSC(raw []byte) error {
+	if b.tx == nil {
+		return fmt.Errorf("the bridge does not forward OSC to sACN")
+	}
+	messages, err := decodePacket(raw)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf("the bridge is closed")
+	}
+	changed := make(map[uint16]bool)
+	for _, m := range messages {
+		universe, slot, err := parseSlotAddress(m.Address)
+		if err != nil || len(m.Args) != 1 {
+			continue
+		}
+		frame, ok := b.frames[universe]
+		if !ok {
+			frame = make([]byte, 512)
+			b.frames[universe] = frame
+		}
+		frame[slot-1] = clamp(m.Args[0])
+		changed[universe] = true
+	}
+	for universe := range changed {
+		ch, ok := b.tx.Channel(universe)
+		if !ok {
+			ch, err = b.tx.Activate(universe)
+			if err != nil {
+				return err
+			}
+			if old, ok := b.activated[universe]; ok {
+				close(old) //the universe was deactivated by someone else
+			}
+			b.activated[universe] = ch
+		}
+		ch <- append([]byte(nil), b.frames[universe]...)
+	}
+	return nil
+}
+
+func clamp(value int32) byte {
+	if value < 0 {
+		return 0
+	}
+	if value > 255 {
+		return 255
+	}
+	return byte(value)
+}
+
+// Close stops the bridge. Universes that were activated by the bridge are deactivated. It can be called
+// multiple times, further calls return nil. HandleOSC returns an error after the bridge was closed.
+func (b *Bridge) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		err = b.close()
+	})
+	return err
+}
+
+func (b *Bridge) close() error {
+	close(b.stop)
+	var err error
+	if b.out != nil {
+		err = b.out.Close()
+	}
+	b.mu.Lock()
+	b.closed = true
+	if b.in != nil {
+		err = b.in.Close()
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for universe, ch := range b.activated {
+		//the universe may have been deactivated already, e.g. by closing the transmitter
+		b.tx.Deactivate(universe)
+		close(ch)
+		delete(b.activated, universe)
+	}
+	return err
+}
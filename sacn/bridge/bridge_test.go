@@ -0,0 +1,194 @@
+package bridge
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+func TestEncodeMessage(t *testing.T) {
+	out := encodeMessage("/sacn/1/2", 255)
+	shouldBe := []byte("/sacn/1/2\x00\x00\x00,i\x00\x00\x00\x00\x00\xff")
+	if !bytes.Equal(out, shouldBe) {
+		t.Errorf("Wrong output! Was: %q; Should've been: %q", out, shouldBe)
+	}
+	//an address with a length of a multiple of 4 still needs the null terminator
+	out = encodeMessage("/sacn/1/10", 1)
+	if len(out) != 12+4+4 {
+		t.Errorf("Wrong length! Was: %v; Should've been: %v", len(out), 20)
+	}
+}
+
+func TestFrameBundle(t *testing.T) {
+	b := &Bridge{last: make(map[uint16][]byte)}
+	//the first frame of a universe is sent completely
+	bundle := b.frameBundle(7, []byte{0, 10})
+	messages, err := decodePacket(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shouldBe := []oscMessage{
+		{Address: "/sacn/7/1", Args: []int32{0}},
+		{Address: "/sacn/7/2", Args: []int32{10}},
+	}
+	if !reflect.DeepEqual(messages, shouldBe) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", messages, shouldBe)
+	}
+	//only the changed slots are sent
+	bundle = b.frameBundle(7, []byte{0, 20, 30})
+	messages, err = decodePacket(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shouldBe = []oscMessage{
+		{Address: "/sacn/7/2", Args: []int32{20}},
+		{Address: "/sacn/7/3", Args: []int32{30}},
+	}
+	if !reflect.DeepEqual(messages, shouldBe) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", messages, shouldBe)
+	}
+	if !bytes.HasPrefix(bundle, []byte("#bundle\x00\x00\x00\x00\x00\x00\x00\x00\x01")) {
+		t.Errorf("Wrong bundle header! Was: %q", bundle[:16])
+	}
+	//nothing changed
+	if bundle = b.frameBundle(7, []byte{0, 20, 30}); bundle != nil {
+		t.Errorf("Wrong output! Was: %q; Should've been: nil", bundle)
+	}
+}
+
+func TestDecodePacketInvalid(t *testing.T) {
+	tests := [][]byte{
+		[]byte("/sacn/1/1"), //no null terminator
+		[]byte("sacn\x00\x00\x00\x00,i\x00\x00\x00\x00\x00\x01"),                   //no leading slash
+		[]byte("/a\x00\x00,f\x00\x00\x00\x00\x00\x01"),                             //unsupported type
+		[]byte("/a\x00\x00,i\x00\x00\x00\x01"),                                     //truncated argument
+		append([]byte("#bundle\x00\x00\x00\x00\x00\x00\x00\x00\x01"), 0, 0, 0, 20), //truncated element
+	}
+	for _, raw := range tests {
+		if _, err := decodePacket(raw); err == nil {
+			t.Errorf("Decoding %q did not return an error!", raw)
+		}
+	}
+}
+
+func TestParseSlotAddress(t *testing.T) {
+	universe, slot, err := parseSlotAddress("/sacn/63999/512")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if universe != 63999 || slot != 512 {
+		t.Errorf("Wrong output! Was: %v/%v; Should've been: %v/%v", universe, slot, 63999, 512)
+	}
+	for _, invalid := range []string{"/sacn/1", "/osc/1/1", "/sacn/1/0", "/sacn/1/513", "/sacn/70000/1", "sacn/1/1/"} {
+		if _, _, err := parseSlotAddress(invalid); err == nil {
+			t.Errorf("Parsing %v did not return an error!", invalid)
+		}
+	}
+}
+
+// recordingSender records all packets the transmitter sends
+type recordingSender struct {
+	mu      sync.Mutex
+	packets []sacn.DataPacket
+}
+
+func (s *recordingSender) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	p, err := sacn.NewDataPacketRaw(b)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.packets = append(s.packets, p)
+	s.mu.Unlock()
+	return len(b), nil
+}
+
+func (s *recordingSender) Close() error {
+	return nil
+}
+
+func TestHandleOSC(t *testing.T) {
+	tx, err := sacn.NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	tx.SetPacketSenderFactory(func(bind string) (sacn.PacketSender, error) {
+		return sender, nil
+	})
+	tx.SetMulticast(3, true)
+	b, err := NewOSCToSACNBridge(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := encodeBundle([][]byte{
+		encodeMessage("/sacn/3/1", 100),
+		encodeMessage("/sacn/3/3", 300), //clamped to 255
+		encodeMessage("/other", 1),      //ignored
+	})
+	if err := b.HandleOSC(bundle); err != nil {
+		t.Fatal(err)
+	}
+	if !tx.IsActivated(3) {
+		t.Fatal("The universe was not activated by the bridge!")
+	}
+	shouldBe := append([]byte{100, 0, 255}, make([]byte, 509)...)
+	for i := 0; ; i++ {
+		if i > 100 {
+			t.Fatal("The frame was not sent!")
+		}
+		sender.mu.Lock()
+		found := false
+		for _, p := range sender.packets {
+			found = found || bytes.Equal(p.Data(), shouldBe)
+		}
+		sender.mu.Unlock()
+		if found {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; tx.IsActivated(3); i++ {
+		if i > 100 {
+			t.Fatal("The universe was not deactivated by closing the bridge!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCloseBridge(t *testing.T) {
+	tx, err := sacn.NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.SetPacketSenderFactory(func(bind string) (sacn.PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	b, err := NewOSCToSACNBridge(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.HandleOSC(encodeMessage("/sacn/3/1", 100)); err != nil {
+		t.Fatal(err)
+	}
+	//the transmitter is closed before the bridge
+	tx.Close()
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("Closing the bridge twice returned an error: %v", err)
+	}
+	if err := b.HandleOSC(encodeMessage("/sacn/3/1", 50)); err == nil {
+		t.Error("Err was nil! Should have been an error, because the bridge is closed!")
+	}
+}
@@ -0,0 +1,145 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// oscMessage is an OSC message with int32 arguments. Other argument types are not supported.
+type oscMessage struct {
+	Address string
+	Args    []int32
+}
+
+var bundleHeader = []byte("#bundle\x00")
+
+// appendOSCString appends the string null terminated and padded to a multiple of 4 bytes
+func appendOSCString(b []byte, s string) []byte {
+	b = append(b, s...)
+	return append(b, make([]byte, 4-len(s)%4)...)
+}
+
+// encodeMessage encodes an OSC message with a single int32 argument
+func encodeMessage(address string, value int32) []byte {
+	b := appendOSCString(make([]byte, 0, len(address)+12), address)
+	b = appendOSCString(b, ",i")
+	var arg [4]byte
+	binary.BigEndian.PutUint32(arg[:], uint32(value))
+	return append(b, arg[:]...)
+}
+
+// encodeBundle encodes the messages into an OSC bundle with the time tag "immediately"
+func encodeBundle(messages [][]byte) []byte {
+	b := append([]byte(nil), bundleHeader...)
+	b = append(b, 0, 0, 0, 0, 0, 0, 0, 1) //time tag 1 means immediately
+	var size [4]byte
+	for _, m := range messages {
+		binary.BigEndian.PutUint32(size[:], uint32(len(m)))
+		b = append(b, size[:]...)
+		b = append(b, m...)
+	}
+	return b
+}
+
+// decodePacket decodes an OSC packet, which is either a message or a bundle. The messages of nested
+// bundles are returned in the order they appear in the packet.
+func decodePacket(raw []byte) ([]oscMessage, error) {
+	if !bytes.HasPrefix(raw, bundleHeader) {
+		m, err := decodeMessage(raw)
+		if err != nil {
+			return nil, err
+		}
+		return []oscMessage{m}, nil
+	}
+	messages := make([]oscMessage, 0)
+	rest := raw[len(bundleHeader):]
+	if len(rest) < 8 {
+		return nil, fmt.Errorf("the bundle has no time tag")
+	}
+	rest = rest[8:]
+	for len(rest) > 0 {
+		if len(rest) < 4 {
+			return nil, fmt.Errorf("the bundle element size is truncated")
+		}
+		size := binary.BigEndian.Uint32(rest[:4])
+		rest = rest[4:]
+		if uint32(len(rest)) < size {
+			return nil, fmt.Errorf("the bundle element was %v bytes long, but only %v bytes are left", size, len(rest))
+		}
+		inner, err := decodePacket(rest[:size])
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, inner...)
+		rest = rest[size:]
+	}
+	return messages, nil
+}
+
+// decodeMessage decodes a single OSC message. Only int32 arguments are supported.
+func decodeMessage(raw []byte) (oscMessage, error) {
+	address, rest, err := readOSCString(raw)
+	if err != nil {
+		return oscMessage{}, err
+	}
+	if !strings.HasPrefix(address, "/") {
+		return oscMessage{}, fmt.Errorf("the address %q does not start with /", address)
+	}
+	tags, rest, err := readOSCString(rest)
+	if err != nil {
+		return oscMessage{}, err
+	}
+	if !strings.HasPrefix(tags, ",") {
+		return oscMessage{}, fmt.Errorf("the type tags %q do not start with a comma", tags)
+	}
+	m := oscMessage{Address: address, Args: make([]int32, 0, len(tags)-1)}
+	for _, tag := range tags[1:] {
+		if tag != 'i' {
+			return oscMessage{}, fmt.Errorf("the argument type %q is not supported", tag)
+		}
+		if len(rest) < 4 {
+			return oscMessage{}, fmt.Errorf("the argument of %v is truncated", address)
+		}
+		m.Args = append(m.Args, int32(binary.BigEndian.Uint32(rest[:4])))
+		rest = rest[4:]
+	}
+	return m, nil
+}
+
+// readOSCString reads a null terminated and padded string and returns the remaining bytes
+func readOSCString(raw []byte) (string, []byte, error) {
+	end := bytes.IndexByte(raw, 0)
+	if end < 0 {
+		return "", nil, fmt.Errorf("the string is not null terminated")
+	}
+	padded := end + 4 - end%4
+	if padded > len(raw) {
+		return "", nil, fmt.Errorf("the string is not padded to 4 bytes")
+	}
+	return string(raw[:end]), raw[padded:], nil
+}
+
+// slotAddress returns the OSC address of the given slot. Slots are numbered from 1 to 512.
+func slotAddress(universe uint16, slot int) string {
+	return fmt.Sprintf("/sacn/%v/%v", universe, slot)
+}
+
+// parseSlotAddress parses an address of the form /sacn/{universe}/{slot}
+func parseSlotAddress(address string) (universe uint16, slot int, err error) {
+	parts := strings.Split(address, "/")
+	if len(parts) != 4 || parts[0] != "" || parts[1] != "sacn" {
+		return 0, 0, fmt.Errorf("the address %q does not match /sacn/{universe}/{slot}", address)
+	}
+	u, err := strconv.ParseUint(parts[2], 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("the universe of the address %q is invalid: %v", address, err)
+	}
+	slot, err = strconv.Atoi(parts[3])
+	if err != nil || slot < 1 || slot > 512 {
+		return 0, 0, fmt.Errorf("the slot of the address %q is not in range [1-512]", address)
+	}
+	return uint16(u), slot, nil
+}
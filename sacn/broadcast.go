@@ -0,0 +1,65 @@
+package sacn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// BroadcastToAllDestinations sets the given data on all activated universes and sends it out
+// immediately to all destinations, e.g. for an emergency blackout. If data is nil, 512 zeros are sent.
+// The universes are sent in parallel. This is a best-effort operation: all universes are tried and the
+// errors of all universes are returned combined. If the context is canceled, the destinations that were
+// not sent yet are skipped and the error of the context is part of the returned error.
+// Paused universes get the data, but it is only sent out after Resume, like data written to their channels.
+// Note: the data stays on the universes until new data is written to their channels.
+func (t *Transmitter) BroadcastToAllDestinations(ctx context.Context, data []byte) error {
+	if data == nil {
+		data = make([]byte, 512)
+	}
+	if len(data) > 512 {
+		return fmt.Errorf("the data length was %v and therefore longer than 512 bytes", len(data))
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make([]string, 0)
+	addErr := func(universe uint16, err error) {
+		mu.Lock()
+		errs = append(errs, fmt.Sprintf("universe %v: %v", universe, err))
+		mu.Unlock()
+	}
+	t.mu.RLock()
+	conns := make(map[uint16]*universeConn, len(t.conns))
+	packets := make(map[uint16]*DataPacket, len(t.master))
+	paused := make(map[uint16]*int32, len(t.paused))
+	for universe, conn := range t.conns {
+		conns[universe], packets[universe], paused[universe] = conn, t.master[universe], t.paused[universe]
+	}
+	t.mu.RUnlock()
+	for universe, conn := range conns {
+		wg.Add(1)
+		go func(universe uint16, conn *universeConn, packet *DataPacket, paused *int32) {
+			defer wg.Done()
+			if err := ctx.Err(); err != nil {
+				addErr(universe, err)
+				return
+			}
+			t.packetMu.Lock()
+			packet.SetData(data)
+			t.packetMu.Unlock()
+			if atomic.LoadInt32(paused) != 0 {
+				return
+			}
+			if err := t.sendOutContext(ctx, conn, universe); err != nil {
+				addErr(universe, err)
+			}
+		}(universe, conn, packets[universe], paused[universe])
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return fmt.Errorf("broadcast failed: %v", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package sacn
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBroadcastToAllDestinations(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	for _, univ := range []uint16{1, 2, 3} {
+		trans.SetRawDestinations(univ, []net.UDPAddr{{IP: net.IPv4(127, 0, 0, 1), Port: port}})
+		ch, err := trans.ActivateWithData(univ, []byte{1, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer close(ch)
+		readTestPacket(t, conn)
+	}
+	//paused universes are not sent
+	if err := trans.Pause(3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := trans.BroadcastToAllDestinations(context.Background(), []byte{7, 7}); err != nil {
+		t.Fatal(err)
+	}
+	received := make(map[uint16]bool)
+	for i := 0; i < 2; i++ {
+		p := readTestPacket(t, conn)
+		if !bytes.Equal(p.Data(), []byte{7, 7}) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), []byte{7, 7})
+		}
+		received[p.Universe()] = true
+	}
+	if len(received) != 2 || !received[1] || !received[2] {
+		t.Errorf("Wrong universes received the broadcast! Was: %v; Should've been: %v", received, []uint16{1, 2})
+	}
+	trans.packetMu.Lock()
+	if data := trans.master[3].Data(); !bytes.Equal(data, []byte{7, 7}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{7, 7})
+	}
+	trans.packetMu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := trans.BroadcastToAllDestinations(ctx, nil); err == nil {
+		t.Error("A canceled context should abort the broadcast!")
+	}
+	if data := trans.master[1].Data(); !bytes.Equal(data, []byte{7, 7}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{7, 7})
+	}
+}
+
+// countingSlowConn needs some time for every write and counts the writes
+type countingSlowConn struct {
+	slowConn
+	mu      sync.Mutex
+	written int
+}
+
+func (c *countingSlowConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	n, err := c.slowConn.WriteToUDP(b, addr)
+	c.mu.Lock()
+	c.written++
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *countingSlowConn) writes() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.written
+}
+
+func TestBroadcastCanceledDuringSend(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &countingSlowConn{slowConn: slowConn{delay: 50 * time.Millisecond}}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinations(1, []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4"})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	for i := 0; sender.writes() < 4; i++ {
+		if i > 100 {
+			t.Fatal("No keep alive packet was sent!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	//the context is canceled while the second destination is sent
+	ctx, cancel := context.WithTimeout(context.Background(), 75*time.Millisecond)
+	defer cancel()
+	if err := trans.BroadcastToAllDestinations(ctx, nil); err == nil {
+		t.Error("A canceled context should abort the broadcast!")
+	}
+	if written := sender.writes() - 4; written != 2 {
+		t.Errorf("Wrong number of sent destinations! Was: %v; Should've been: %v", written, 2)
+	}
+}
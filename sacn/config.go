@@ -0,0 +1,224 @@
+package sacn
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// TransmitterConfig holds all settings of a Transmitter and its universes. It can be marshaled to JSON,
+// so the configuration can be stored or handed over to another process.
+// Use Transmitter.ExportConfig to get the config and NewTransmitterFromConfig to restore it.
+type TransmitterConfig struct {
+	Bind               string                    `json:"bind"`
+	CID                string                    `json:"cid"` //UUID string like "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+	SourceName         string                    `json:"sourceName"`
+	KeepAlive          time.Duration             `json:"keepAlive"`
+	Priority           byte                      `json:"priority"`
+	MulticastTTL       int                       `json:"multicastTTL"`
+	MulticastInterface string                    `json:"multicastInterface,omitempty"` //the name of the interface
+	Validate           bool                      `json:"validate"`
+	MaxFrameRate       float64                   `json:"maxFrameRate"`
+	Port               int                       `json:"port"`
+	LimitPriority      bool                      `json:"limitPriority"`
+	MaxPriority        byte                      `json:"maxPriority"` //see WithPriorityLimiter
+	RTP                bool                      `json:"rtp"`
+	RTPPayloadType     byte                      `json:"rtpPayloadType"` //see WithRTPTransport
+	Universes          map[uint16]UniverseConfig `json:"universes"`
+}
+
+// UniverseConfig holds the settings of a single universe of a TransmitterConfig.
+type UniverseConfig struct {
+	Multicast             bool            `json:"multicast"`
+	Destinations          []string        `json:"destinations"`          //addresses like "192.168.1.2:5568"
+	DestinationPriorities map[string]byte `json:"destinationPriorities"` //see SetPerDestinationPriority
+	SourceName            string          `json:"sourceName,omitempty"`  //see SetUniverseSourceName
+	CID                   string          `json:"cid,omitempty"`         //see SetUniverseCID
+}
+
+// ExportConfig returns all settings of the transmitter and its universes. The activation state and the
+// DMX data of the universes is not part of the config.
+func (t *Transmitter) ExportConfig() TransmitterConfig {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	cfg := TransmitterConfig{
+		Bind:           t.bind,
+		CID:            FormatCID(t.cid),
+		SourceName:     t.sourceName,
+		KeepAlive:      t.keepAliveInterval,
+		Priority:       t.priority,
+		MulticastTTL:   t.multicastTTL,
+		Validate:       t.validate,
+		MaxFrameRate:   t.maxFrameRate,
+		Port:           t.port,
+		LimitPriority:  t.limitPriority,
+		MaxPriority:    t.maxPriority,
+		RTP:            t.rtp,
+		RTPPayloadType: t.rtpPayloadType,
+		Universes:      make(map[uint16]UniverseConfig),
+	}
+	if t.multicastIfi != nil {
+		cfg.MulticastInterface = t.multicastIfi.Name
+	}
+	universe := func(univ uint16) UniverseConfig {
+		u, ok := cfg.Universes[univ]
+		if !ok {
+			u = UniverseConfig{
+				Destinations:          make([]string, 0),
+				DestinationPriorities: make(map[string]byte),
+			}
+		}
+		return u
+	}
+	t.destMu.RLock()
+	defer t.destMu.RUnlock()
+	for univ, multicast := range t.multicast {
+		u := universe(univ)
+		u.Multicast = multicast
+		cfg.Universes[univ] = u
+	}
+	for univ, dests := range t.destinations {
+		u := universe(univ)
+		for _, dest := range dests {
+			u.Destinations = append(u.Destinations, dest.String())
+		}
+		cfg.Universes[univ] = u
+	}
+	for univ, prios := range t.destPriorities {
+		u := universe(univ)
+		for dest, prio := range prios {
+			u.DestinationPriorities[dest] = prio
+		}
+		cfg.Universes[univ] = u
+	}
+	for univ, name := range t.sourceNames {
+		u := universe(univ)
+		u.SourceName = name
+		cfg.Universes[univ] = u
+	}
+	for univ, cid := range t.cids {
+		u := universe(univ)
+		u.CID = FormatCID(cid)
+		cfg.Universes[univ] = u
+	}
+	return cfg
+}
+
+// NewTransmitterFromConfig creates a new Transmitter with the settings of the given config.
+// No universe is activated, this has to be done by the caller. Settings that can not be stored in a
+// config, like the error channel, can be provided via additional options.
+func NewTransmitterFromConfig(cfg TransmitterConfig, opts ...TransmitterOption) (Transmitter, error) {
+	cid, err := ParseCID(cfg.CID)
+	if err != nil {
+		return Transmitter{}, err
+	}
+	options := []TransmitterOption{
+		WithMulticastTTL(cfg.MulticastTTL),
+		WithMaxFrameRate(cfg.MaxFrameRate),
+		WithPort(cfg.Port),
+	}
+	if cfg.MulticastInterface != "" {
+		ifi, err := net.InterfaceByName(cfg.MulticastInterface)
+		if err != nil {
+			return Transmitter{}, err
+		}
+		options = append(options, WithMulticastInterface(ifi))
+	}
+	if cfg.Validate {
+		options = append(options, WithPacketValidation())
+	}
+	if cfg.LimitPriority {
+		options = append(options, WithPriorityLimiter(cfg.MaxPriority))
+	}
+	if cfg.RTP {
+		options = append(options, WithRTPTransport(cfg.RTPPayloadType))
+	}
+	t, err := NewTransmitter(cfg.Bind, cid, cfg.SourceName, append(options, opts...)...)
+	if err != nil {
+		return t, err
+	}
+	if cfg.KeepAlive > 0 {
+		if err := t.SetKeepAlive(cfg.KeepAlive); err != nil {
+			return t, err
+		}
+	}
+	t.SetPriority(cfg.Priority)
+	for univ, u := range cfg.Universes {
+		if err := t.applyUniverseConfig(univ, u); err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
+
+// checkUniverseConfig returns the first error that applyUniverseConfig would return for the universe
+// config, without changing anything
+func checkUniverseConfig(univ uint16, u UniverseConfig) error {
+	dests := make(map[string]bool, len(u.Destinations))
+	for _, dest := range u.Destinations {
+		addr, err := net.ResolveUDPAddr("udp", dest)
+		if err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+		dests[addr.String()] = true
+	}
+	for dest, prio := range u.DestinationPriorities {
+		addr, err := net.ResolveUDPAddr("udp", dest)
+		if err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+		if prio > 200 {
+			return fmt.Errorf("the priority was %v and therefore is not in range [0-200]", prio)
+		}
+		if !dests[addr.String()] {
+			return fmt.Errorf("the destination %v is not set for universe %v", addr.String(), univ)
+		}
+	}
+	if len(u.SourceName) > 63 {
+		return fmt.Errorf("the source name was %v bytes long and therefore longer than 63 bytes", len(u.SourceName))
+	}
+	if u.CID != "" {
+		if _, err := ParseCID(u.CID); err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+	}
+	return nil
+}
+
+// applyUniverseConfig applies the settings of the universe config to the universe
+func (t *Transmitter) applyUniverseConfig(univ uint16, u UniverseConfig) error {
+	t.SetMulticast(univ, u.Multicast)
+	dests := make([]net.UDPAddr, 0, len(u.Destinations))
+	for _, dest := range u.Destinations {
+		addr, err := net.ResolveUDPAddr("udp", dest)
+		if err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+		dests = append(dests, *addr)
+	}
+	t.SetRawDestinations(univ, dests)
+	for dest, prio := range u.DestinationPriorities {
+		addr, err := net.ResolveUDPAddr("udp", dest)
+		if err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+		if err := t.SetPerDestinationPriority(univ, *addr, prio); err != nil {
+			return err
+		}
+	}
+	if u.SourceName != "" {
+		if err := t.SetUniverseSourceName(univ, u.SourceName); err != nil {
+			return err
+		}
+	}
+	if u.CID != "" {
+		cid, err := ParseCID(u.CID)
+		if err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+		if err := t.SetUniverseCID(univ, cid); err != nil {
+			return fmt.Errorf("universe %v: %v", univ, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sacn
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1, 2, 3}, "test", WithMulticastTTL(8), WithPort(6000),
+		WithPriorityLimiter(120), WithRTPTransport(97))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetKeepAlive(500 * time.Millisecond)
+	trans.SetPriority(150)
+	trans.SetMulticast(1, true)
+	dest := net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5568}
+	trans.SetRawDestinations(2, []net.UDPAddr{dest})
+	if err := trans.SetPerDestinationPriority(2, dest, 200); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SetUniverseSourceName(2, "second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SetUniverseCID(2, [16]byte{9}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := trans.ExportConfig()
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded TransmitterConfig
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewTransmitterFromConfig(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := restored.ExportConfig(); !reflect.DeepEqual(out, cfg) {
+		t.Errorf("Wrong output! Was: %+v; Should've been: %+v", out, cfg)
+	}
+	if restored.cid != trans.cid {
+		t.Errorf("Wrong CID! Was: %v; Should've been: %v", restored.cid, trans.cid)
+	}
+	if !restored.limitPriority || restored.maxPriority != 120 {
+		t.Errorf("Wrong priority limit! Was: %v, %v; Should've been: %v, %v", restored.limitPriority, restored.maxPriority, true, 120)
+	}
+	if !restored.rtp || restored.rtpPayloadType != 97 {
+		t.Errorf("Wrong RTP transport! Was: %v, %v; Should've been: %v, %v", restored.rtp, restored.rtpPayloadType, true, 97)
+	}
+}
@@ -0,0 +1,68 @@
+package sacn
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// the interval in which the frames of a crossfade are written, approximately 44 frames per second
+const crossfadeInterval = time.Second / 44
+
+// CrossfadeUniverse fades linearly from the data of fromUniverse to the data of toUniverse over the given
+// duration, e.g. for switching between two consoles. Approximately 44 times per second, the latest data of
+// both universes (see GetUniverseData) is interpolated by the elapsed time and written to output. A
+// universe that has not received any data yet counts as all zeros. After the duration, a final frame with
+// the data of toUniverse is written and the crossfade ends. The output channel is not closed.
+// Writing to output blocks the crossfade, so the channel should be read fast enough.
+// An error is returned if one of the universes is invalid or the duration is negative.
+func (r *ReceiverSocket) CrossfadeUniverse(fromUniverse, toUniverse uint16, duration time.Duration, output chan<- []byte) error {
+	if err := checkListenUniverse(fromUniverse); err != nil {
+		return err
+	}
+	if err := checkListenUniverse(toUniverse); err != nil {
+		return err
+	}
+	if duration < 0 {
+		return fmt.Errorf("the duration was %v and must not be negative", duration)
+	}
+	start := r.now()
+	go func() {
+		ticker := time.NewTicker(crossfadeInterval)
+		defer ticker.Stop()
+		for {
+			elapsed := r.now().Sub(start)
+			if elapsed >= duration {
+				break
+			}
+			from, _ := r.GetUniverseData(fromUniverse)
+			to, _ := r.GetUniverseData(toUniverse)
+			output <- crossfadeFrame(from, to, float64(elapsed)/float64(duration))
+			<-ticker.C
+		}
+		to, _ := r.GetUniverseData(toUniverse)
+		output <- crossfadeFrame(nil, to, 1)
+	}()
+	return nil
+}
+
+// crossfadeFrame interpolates every slot between from and to. A fraction of 0 returns from, 1 returns
+// to. The shorter frame is filled up with zeros.
+func crossfadeFrame(from, to []byte, fraction float64) []byte {
+	length := len(from)
+	if len(to) > length {
+		length = len(to)
+	}
+	frame := make([]byte, length)
+	for i := range frame {
+		var a, b float64
+		if i < len(from) {
+			a = float64(from[i])
+		}
+		if i < len(to) {
+			b = float64(to[i])
+		}
+		frame[i] = byte(math.Round(a + (b-a)*fraction))
+	}
+	return frame
+}
@@ -0,0 +1,80 @@
+package sacn
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCrossfadeFrame(t *testing.T) {
+	from := []byte{0, 100, 255, 10}
+	to := []byte{255, 0, 255}
+	tests := []struct {
+		fraction float64
+		should   []byte
+	}{
+		{0, []byte{0, 100, 255, 10}},
+		{0.25, []byte{64, 75, 255, 8}},
+		{0.5, []byte{128, 50, 255, 5}},
+		{1, []byte{255, 0, 255, 0}},
+	}
+	for _, test := range tests {
+		if frame := crossfadeFrame(from, to, test.fraction); !bytes.Equal(frame, test.should) {
+			t.Errorf("Wrong output at %v! Was: %v; Should've been: %v", test.fraction, frame, test.should)
+		}
+	}
+}
+
+func TestCrossfadeUniverse(t *testing.T) {
+	r := newReceiverSocket()
+	var mu sync.Mutex
+	now := time.Unix(100, 0)
+	r.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{0, 200}))
+	r.handle(newTestPacket(t, 2, [16]byte{2}, 100, []byte{100, 0}))
+
+	if err := r.CrossfadeUniverse(0, 2, time.Second, nil); err == nil {
+		t.Error("Crossfading from universe 0 should fail!")
+	}
+	if err := r.CrossfadeUniverse(1, 2, -time.Second, nil); err == nil {
+		t.Error("Crossfading with a negative duration should fail!")
+	}
+	output := make(chan []byte)
+	if err := r.CrossfadeUniverse(1, 2, time.Second, output); err != nil {
+		t.Fatal(err)
+	}
+	//the next frame is computed one tick after the previous one was read, so the clock is advanced before
+	steps := []struct {
+		advance time.Duration
+		should  []byte
+	}{
+		{500 * time.Millisecond, []byte{0, 200}},
+		{250 * time.Millisecond, []byte{50, 100}},
+		{250 * time.Millisecond, []byte{75, 50}},
+	}
+	for _, step := range steps {
+		frame := <-output
+		if !bytes.Equal(frame, step.should) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", frame, step.should)
+		}
+		advance(step.advance)
+	}
+	if frame := <-output; !bytes.Equal(frame, []byte{100, 0}) {
+		t.Errorf("Wrong final frame! Was: %v; Should've been: %v", frame, []byte{100, 0})
+	}
+	select {
+	case frame := <-output:
+		t.Errorf("A frame was written after the crossfade: %v", frame)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -1,8 +1,8 @@
 package sacn
 
 import (
+	"bytes"
 	"fmt"
-	"math"
 )
 
 const (
@@ -14,15 +14,18 @@ const (
 var constHeader = []byte{0, 0x10, 0, 0, 0x41, 0x53,
 	0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00}
 
-// DataPacket is a byte array with unspecific length
+// DataPacket is a byte array with unspecific length. The packet is stored in a fixed array, that is big
+// enough for 512 DMX slots, so changing the packet does not allocate.
+// Every setter has a getter with the same name without the Set prefix, e.g. CID for SetCID. Following
+// the Go naming conventions, the getters have no Get prefix.
 type DataPacket struct {
-	data   []byte
+	data   [638]byte
 	length uint16
 }
 
-// NewDataPacket creates a new DataPacket with an empty 638-length byte slice
+// NewDataPacket creates a new DataPacket without any DMX data
 func NewDataPacket() DataPacket {
-	p := DataPacket{make([]byte, 638), 126}
+	p := DataPacket{length: 126}
 	//Set constants: at index [0;16[
 	p.replace(0, constHeader)
 	//Set vectors:
@@ -41,6 +44,25 @@ func NewDataPacket() DataPacket {
 	return p
 }
 
+// NewDataPacketForUniverse creates a new DataPacket that is ready to be sent out on the given universe.
+// The universe has to be in range [1-63999] and the source name must not be longer than 63 bytes.
+// The DMX start code is set to 0x00.
+func NewDataPacketForUniverse(universe uint16, cid [16]byte, sourceName string) (DataPacket, error) {
+	var p DataPacket
+	if !IsValidDataUniverse(universe) {
+		return p, fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", universe)
+	}
+	if len(sourceName) > 63 {
+		return p, fmt.Errorf("the source name was %v bytes long and therefore longer than 63 bytes", len(sourceName))
+	}
+	p = NewDataPacket()
+	p.SetUniverse(universe)
+	p.SetCID(cid)
+	p.SetSourceName(sourceName)
+	p.SetDmxStartCode(0x00)
+	return p, nil
+}
+
 // NewDataPacketRaw creates a new DataPacket based on the given raw bytes
 func NewDataPacketRaw(raw []byte) (DataPacket, error) {
 	var p DataPacket
@@ -49,17 +71,28 @@ func NewDataPacketRaw(raw []byte) (DataPacket, error) {
 		return p, fmt.Errorf("The given raw bytes are too short! Min length is 126 was %v", len(raw))
 	}
 	p = NewDataPacket()
-	//Make the array 638 long
-	if len(raw) < 638 { //Add 0 if too short
-		raw = append(raw, make([]byte, 638-len(raw))...)
-	} else if len(raw) > 638 { //cut off the last bits if too long
-		raw = raw[:638]
+	//the property value count includes the start code, so it has to be in range [1-513]
+	if count := getAsUint32(raw[123:125]); count < 1 || count > 513 {
+		return p, fmt.Errorf("The property value count was %v and therefore is not in range [1-513]", count)
 	}
-	p.data = append([]byte(nil), raw...) //make a copy of the slice, we do not want to use a reference
+	copy(p.data[:], raw) //bytes that are longer than 638 are cut off
 	p.length = uint16(getAsUint32(raw[123:125]) + 125)
 	return p, nil
 }
 
+// ParseDataPacket parses the raw bytes like NewDataPacketRaw, but additionally validates the packet.
+// If the packet violates the E1.31 specification (see Validate), the first violation is returned as error.
+func ParseDataPacket(raw []byte) (DataPacket, error) {
+	p, err := NewDataPacketRaw(raw)
+	if err != nil {
+		return p, err
+	}
+	if errs := p.Validate(); len(errs) > 0 {
+		return p, errs[0]
+	}
+	return p, nil
+}
+
 // Set the FAL values in the byte slice according to the length
 // Note: Length is the length of the whole message!
 // Also sets the property value count!
@@ -80,18 +113,91 @@ func (d *DataPacket) setFAL(length uint16) {
 
 // replace everything starting from the start index in the DataPacket with the given replacement
 func (d *DataPacket) replace(startIndex int, replacement []byte) {
-	d.data = append(d.data[:startIndex],
-		append(replacement, d.data[len(replacement)+startIndex:]...)...)
+	copy(d.data[startIndex:], replacement)
 }
 
 // copy returns a copy of the DataPacket
 func (d *DataPacket) copy() DataPacket {
-	copySlice := make([]byte, len(d.data))
-	copy(copySlice, d.data)
-	return DataPacket{
-		data:   copySlice,
-		length: d.length,
+	return *d
+}
+
+// Equal returns true if both packets have the same CID, source name, priority, sync address, sequence
+// number, options, universe, start code and DMX data. The fields are compared directly, so this is
+// faster than comparing the bytes of both packets and does not allocate.
+func (d *DataPacket) Equal(other DataPacket) bool {
+	return d.length == other.length &&
+		d.data[111] == other.data[111] && //sequence
+		d.data[108] == other.data[108] && //priority
+		d.data[112] == other.data[112] && //options
+		d.data[125] == other.data[125] && //start code
+		bytes.Equal(d.data[113:115], other.data[113:115]) && //universe
+		bytes.Equal(d.data[109:111], other.data[109:111]) && //sync address
+		bytes.Equal(d.data[22:38], other.data[22:38]) && //CID
+		bytes.Equal(d.data[44:108], other.data[44:108]) && //source name
+		bytes.Equal(d.data[126:d.length], other.data[126:other.length]) //DMX data
+}
+
+// SlotChange is the change of one DMX slot between two packets, see DataPacket.DiffFrom.
+type SlotChange struct {
+	Slot     int //the index of the slot in the data, so slot 0 is DMX channel 1
+	OldValue byte
+	NewValue byte
+}
+
+// DiffFrom returns all slots whose value differs between the previous packet and this packet, e.g. for
+// devices that only accept delta updates. The changes are sorted by slot. Slots that are missing in the
+// shorter packet are treated as 0. If nothing changed, nil is returned without allocating.
+func (d *DataPacket) DiffFrom(prev DataPacket) []SlotChange {
+	newData, oldData := d.data[126:d.length], prev.data[126:prev.length]
+	n := len(newData)
+	if len(oldData) > n {
+		n = len(oldData)
 	}
+	var changes []SlotChange
+	for i := 0; i < n; i++ {
+		var old, new byte
+		if i < len(oldData) {
+			old = oldData[i]
+		}
+		if i < len(newData) {
+			new = newData[i]
+		}
+		if old != new {
+			changes = append(changes, SlotChange{Slot: i, OldValue: old, NewValue: new})
+		}
+	}
+	return changes
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// fnv64a continues the 64-bit FNV-1a hash h with the given bytes
+func fnv64a(h uint64, b []byte) uint64 {
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// Hash returns a 64-bit FNV-1a hash of the universe, CID, priority and DMX data of the packet, e.g. as
+// key for caches. Packets with the same fields have the same hash, but different packets may have the
+// same hash too, so the hash can not replace a full comparison. All other fields, like the sequence
+// number, are ignored.
+func (d *DataPacket) Hash() uint64 {
+	h := fnv64a(fnvOffset64, d.data[113:115]) //universe
+	h = fnv64a(h, d.data[22:38])              //CID
+	h = fnv64a(h, d.data[108:109])            //priority
+	return fnv64a(h, d.data[126:d.length])
+}
+
+// ContentHash returns a 64-bit FNV-1a hash of only the DMX data of the packet, e.g. for detecting changes
+// of the data regardless of the source.
+func (d *DataPacket) ContentHash() uint64 {
+	return fnv64a(fnvOffset64, d.data[126:d.length])
 }
 
 // SetCID sets the CID unique identifier
@@ -192,16 +298,29 @@ func (d *DataPacket) ForceSync() bool {
 	return d.getOptionsBit(5)
 }
 
+// SetOptions overwrites the whole options byte of the packet. E1.31 defines the following bits:
+// bit 7 (0x80) is the preview_data flag, bit 6 (0x40) the stream_terminated flag and bit 5 (0x20) the
+// force_synchronization flag. Bits 0 to 4 are reserved and should be 0, see Validate.
+// Use the setters of the single flags if you do not want to overwrite the other bits.
+func (d *DataPacket) SetOptions(opts byte) {
+	d.data[112] = opts
+}
+
+// Options returns the whole options byte of the packet. See SetOptions for the meaning of the bits.
+func (d *DataPacket) Options() byte {
+	return d.data[112]
+}
+
 func (d *DataPacket) setOptionsBit(bit byte, value bool) {
 	if value {
-		d.data[112] = d.data[112] | byte(math.Pow(2, float64(bit)))
+		d.SetOptions(d.Options() | 1<<bit)
 	} else {
-		d.data[112] = d.data[112] & (byte(math.Pow(2, float64(bit))) ^ 0xFF)
+		d.SetOptions(d.Options() &^ (1 << bit))
 	}
 }
 
 func (d *DataPacket) getOptionsBit(bit byte) bool {
-	return d.data[112]&byte(math.Pow(2, float64(bit))) != 0
+	return d.Options()&(1<<bit) != 0
 }
 
 // SetUniverse sets the universe value of the packet
@@ -224,24 +343,78 @@ func (d *DataPacket) DmxStartCode() byte {
 	return d.data[125]
 }
 
-// SetData sets the dmx data for the given DataPacket
-func (d *DataPacket) SetData(data []byte) {
+// SetData sets the dmx data for the given DataPacket. The data may be 0 to 512 bytes long, for longer
+// data an error is returned and the packet is not changed. The packet has a variable length: the length
+// fields of the PDUs are set according to the length of the data. Data with an odd length gets padded
+// with one 0 byte.
+func (d *DataPacket) SetData(data []byte) error {
 	if len(data) > 512 {
-		data = data[0:512]
+		return fmt.Errorf("the data length was %v and therefore is not in range [0-512]", len(data))
 	}
+	length := len(data)
+	d.replace(126, data)
 	//make the length a multiply of 2
-	if len(data)%2 != 0 { //add a 0 to make the length sufficient
-		data = append(data, 0)
+	if length%2 != 0 { //add a 0 to make the length sufficient
+		d.data[126+length] = 0
+		length++
 	}
-	d.setFAL(uint16(126 + len(data)))
-	d.replace(126, data)
+	d.setFAL(uint16(126 + length))
+	return nil
 }
 
 // Data returns the DMX data that is set for this DataPacket. Length: [0-512]
+// The returned slice points into the packet, so it has to be copied if it is kept while the packet changes.
 func (d *DataPacket) Data() []byte {
 	return d.data[126:d.length]
 }
 
-func (d *DataPacket) getBytes() []byte {
+// Validate checks all fields of the packet against the limits of the E1.31 specification and returns
+// all violations that were found. If the packet is valid, the returned slice is empty.
+func (d *DataPacket) Validate() []error {
+	errs := make([]error, 0)
+	if !bytes.Equal(d.data[0:16], constHeader) {
+		errs = append(errs, fmt.Errorf("the root layer preamble or ACN packet identifier is not correct"))
+	}
+	if vector := getAsUint32(d.data[18:22]); vector != vectorRootE131Data {
+		errs = append(errs, fmt.Errorf("the root layer vector was %v and should have been %v", vector, vectorRootE131Data))
+	}
+	if vector := getAsUint32(d.data[40:44]); vector != vectorE131DataPacket {
+		errs = append(errs, fmt.Errorf("the framing layer vector was %v and should have been %v", vector, vectorE131DataPacket))
+	}
+	if d.data[117] != vectorDmpSetProperty {
+		errs = append(errs, fmt.Errorf("the DMP layer vector was %v and should have been %v", d.data[117], vectorDmpSetProperty))
+	}
+	if d.data[107] != 0 {
+		errs = append(errs, fmt.Errorf("the source name is longer than 63 bytes"))
+	}
+	if prio := d.Priority(); prio > 200 {
+		errs = append(errs, fmt.Errorf("the priority was %v and therefore is not in range [0-200]", prio))
+	}
+	if opts := d.data[112]; opts&0x1F != 0 {
+		errs = append(errs, fmt.Errorf("the options byte %#x uses reserved bits", opts))
+	}
+	if univ := d.Universe(); !IsValidDataUniverse(univ) {
+		errs = append(errs, fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", univ))
+	}
+	if d.length < 126 || d.length > 638 {
+		errs = append(errs, fmt.Errorf("the data length was %v and therefore is not in range [0-512]", int(d.length)-126))
+	}
+	return errs
+}
+
+// Bytes returns the packet in the wire format. The returned slice is a new copy on every call,
+// so modifying it does not change the packet.
+func (d *DataPacket) Bytes() []byte {
+	return append([]byte(nil), d.data[:d.length]...)
+}
+
+// wireBytes returns the packet in the wire format without copying. The slice points to the packet and
+// is only valid until the packet is changed.
+func (d *DataPacket) wireBytes() []byte {
 	return d.data[:d.length]
 }
+
+// Deprecated: use Bytes instead.
+func (d *DataPacket) getBytes() []byte {
+	return d.Bytes()
+}
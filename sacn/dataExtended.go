@@ -0,0 +1,21 @@
+//go:build e131_extended
+// +build e131_extended
+
+package sacn
+
+// extendedOptionsMask are the bits of the options byte that E1.31-2016 reserves for future use
+const extendedOptionsMask = 0x1F
+
+// SetExtendedOptions sets the reserved bits 0 to 4 of the options byte, for forward compatibility with
+// drafts of newer versions of E1.31. Only the lower 5 bits of opts are used, the flags that are defined
+// by E1.31-2016 are not changed. This is only available with the build tag e131_extended.
+// Note that receivers that implement E1.31-2016 may treat packets with reserved bits as invalid.
+func (d *DataPacket) SetExtendedOptions(opts byte) {
+	d.SetOptions(d.Options()&^extendedOptionsMask | opts&extendedOptionsMask)
+}
+
+// ExtendedOptions returns the reserved bits 0 to 4 of the options byte, see SetExtendedOptions.
+// This is only available with the build tag e131_extended.
+func (d *DataPacket) ExtendedOptions() byte {
+	return d.Options() & extendedOptionsMask
+}
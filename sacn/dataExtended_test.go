@@ -0,0 +1,26 @@
+//go:build e131_extended
+// +build e131_extended
+
+package sacn
+
+import "testing"
+
+func TestSetExtendedOptions(t *testing.T) {
+	p := NewDataPacket()
+	p.SetStreamTerminated(true)
+	p.SetExtendedOptions(0xFF)
+	raw, err := NewDataPacketRaw(p.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.ExtendedOptions() != 0x1F {
+		t.Errorf("Wrong output! Was: %#x; Should've been: %#x", raw.ExtendedOptions(), 0x1F)
+	}
+	if !raw.StreamTerminated() || raw.PreviewData() {
+		t.Errorf("The defined flags were changed! Was: %#x", raw.Options())
+	}
+	raw.SetExtendedOptions(0x05)
+	if raw.Options() != 0x45 {
+		t.Errorf("Wrong output! Was: %#x; Should've been: %#x", raw.Options(), 0x45)
+	}
+}
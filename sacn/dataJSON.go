@@ -0,0 +1,79 @@
+package sacn
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// dataPacketJSON is the JSON representation of a DataPacket
+type dataPacketJSON struct {
+	CID         string `json:"cid"`
+	SourceName  string `json:"sourceName"`
+	Universe    uint16 `json:"universe"`
+	Priority    byte   `json:"priority"`
+	SyncAddress uint16 `json:"syncAddress"`
+	Sequence    byte   `json:"sequence"`
+	Options     byte   `json:"options"`
+	StartCode   byte   `json:"startCode"`
+	Data        []int  `json:"data"`
+}
+
+// MarshalJSON encodes the packet as JSON object. The CID is encoded as UUID string and the DMX data
+// as array of numbers.
+func (d DataPacket) MarshalJSON() ([]byte, error) {
+	data := make([]int, 0, len(d.Data()))
+	for _, value := range d.Data() {
+		data = append(data, int(value))
+	}
+	return json.Marshal(dataPacketJSON{
+		CID:         FormatCID(d.CID()),
+		SourceName:  d.SourceName(),
+		Universe:    d.Universe(),
+		Priority:    d.Priority(),
+		SyncAddress: d.SyncAddress(),
+		Sequence:    d.Sequence(),
+		Options:     d.data[112],
+		StartCode:   d.DmxStartCode(),
+		Data:        data,
+	})
+}
+
+// UnmarshalJSON decodes a JSON object that was created by MarshalJSON. Missing fields get the
+// default values of NewDataPacket.
+func (d *DataPacket) UnmarshalJSON(b []byte) error {
+	p := NewDataPacket()
+	aux := dataPacketJSON{
+		CID:      FormatCID(p.CID()),
+		Priority: p.Priority(),
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	cid, err := ParseCID(aux.CID)
+	if err != nil {
+		return err
+	}
+	if err := p.SetPriority(aux.Priority); err != nil {
+		return err
+	}
+	if len(aux.Data) > 512 {
+		return fmt.Errorf("the data length was %v and therefore is not in range [0-512]", len(aux.Data))
+	}
+	data := make([]byte, len(aux.Data))
+	for i, value := range aux.Data {
+		if value < 0 || value > 255 {
+			return fmt.Errorf("the value %v of slot %v is not in range [0-255]", value, i)
+		}
+		data[i] = byte(value)
+	}
+	p.SetCID(cid)
+	p.SetSourceName(aux.SourceName)
+	p.SetUniverse(aux.Universe)
+	p.SetSyncAddress(aux.SyncAddress)
+	p.SetSequence(aux.Sequence)
+	p.data[112] = aux.Options
+	p.SetDmxStartCode(aux.StartCode)
+	p.SetData(data)
+	*d = p
+	return nil
+}
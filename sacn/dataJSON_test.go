@@ -0,0 +1,53 @@
+package sacn
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	p := NewDataPacket()
+	p.SetCID([16]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 1, 2, 3, 4, 5, 6, 7, 8})
+	p.SetSourceName("json source")
+	p.SetUniverse(42)
+	p.SetPriority(150)
+	p.SetSyncAddress(7)
+	p.SetSequence(99)
+	p.SetPreviewData(true)
+	p.SetDmxStartCode(0xDD)
+	p.SetData([]byte{0, 1, 128, 255})
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`"cid":"12345678-9abc-def0-0102-030405060708"`)) ||
+		!bytes.Contains(b, []byte(`"data":[0,1,128,255]`)) {
+		t.Errorf("Wrong output! Was: %s", b)
+	}
+	var out DataPacket
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), p.Bytes()) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out.Bytes(), p.Bytes())
+	}
+}
+
+func TestJSONDefaults(t *testing.T) {
+	var p DataPacket
+	if err := json.Unmarshal([]byte(`{"universe":1}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Universe() != 1 || p.Priority() != 100 || p.CID() != [16]byte{} || len(p.Data()) != 0 {
+		t.Errorf("Wrong defaults! Was: universe %v, priority %v, CID %v, data %v",
+			p.Universe(), p.Priority(), p.CID(), p.Data())
+	}
+	if err := json.Unmarshal([]byte(`{"data":[256]}`), &p); err == nil {
+		t.Error("Err was nil! Should have been an error for a too high value!")
+	}
+	if err := json.Unmarshal([]byte(`{"cid":"not a cid"}`), &p); err == nil {
+		t.Error("Err was nil! Should have been an error for an invalid cid!")
+	}
+}
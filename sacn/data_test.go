@@ -3,6 +3,7 @@ package sacn
 import (
 	"bytes"
 	"math/rand"
+	"reflect"
 	"testing"
 )
 
@@ -126,19 +127,310 @@ func TestSetPreviewData(t *testing.T) {
 	}
 }
 
+func TestSetOptions(t *testing.T) {
+	p := NewDataPacket()
+	p.SetStreamTerminated(true)
+	if o := p.Options(); o != 0x40 {
+		t.Errorf("Wrong output! Was: %#x; Should've been: %#x", o, 0x40)
+	}
+	p.SetPreviewData(true)
+	if o := p.Options(); o != 0xC0 {
+		t.Errorf("Wrong output! Was: %#x; Should've been: %#x", o, 0xC0)
+	}
+	//SetOptions overwrites all bits
+	p.SetOptions(0x21)
+	if o := p.Options(); o != 0x21 {
+		t.Errorf("Wrong output! Was: %#x; Should've been: %#x", o, 0x21)
+	}
+	if p.StreamTerminated() || p.PreviewData() || !p.ForceSync() {
+		t.Error("The flags do not match the options byte 0x21")
+	}
+	p.SetForceSync(false)
+	if o := p.Options(); o != 0x01 {
+		t.Errorf("Wrong output! Was: %#x; Should've been: %#x", o, 0x01)
+	}
+}
+
 func TestSetData(t *testing.T) {
+	for _, length := range []int{0, 1, 256, 512} {
+		p := NewDataPacket()
+		i := make([]byte, length)
+		for j := range i {
+			i[j] = byte(rand.Uint32())
+		}
+		if err := p.SetData(i); err != nil {
+			t.Errorf("Length %v: %v", length, err)
+		}
+		//odd lengths are padded with a 0
+		shouldBe := i
+		if length%2 != 0 {
+			shouldBe = append(i, 0)
+		}
+		if !bytes.Equal(shouldBe, p.Data()) {
+			t.Errorf("DMX data was not set or getted properly! Was: %v \nShouldbe: %v", p.Data(), shouldBe)
+		}
+		if len(p.Bytes()) != 126+len(shouldBe) {
+			t.Errorf("Wrong packet length! Was: %v; Should've been: %v", len(p.Bytes()), 126+len(shouldBe))
+		}
+	}
+	p := NewDataPacket()
+	p.SetData([]byte{1, 2})
+	if err := p.SetData(make([]byte, 513)); err == nil {
+		t.Error("Err was nil! Should have been an error for 513 bytes!")
+	}
+	if !bytes.Equal(p.Data(), []byte{1, 2}) {
+		t.Errorf("The data was changed by the invalid call! Was: %v", p.Data())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := NewDataPacket()
+	p.SetUniverse(1)
+	if errs := p.Validate(); len(errs) != 0 {
+		t.Errorf("A valid packet had errors: %v", errs)
+	}
+	p.SetUniverse(64000)
+	p.data[108] = 201  //priority
+	p.data[112] = 0x01 //reserved option bit
+	p.data[21] = 0x05  //root vector
+	p.data[107] = 'a'  //source name without termination
+	if errs := p.Validate(); len(errs) != 5 {
+		t.Errorf("Wrong output! Was: %v; Should've been 5 errors", errs)
+	}
+}
+
+func TestGettersRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(p *DataPacket)
+		get  func(p *DataPacket) interface{}
+		want interface{}
+	}{
+		{"CID", func(p *DataPacket) { p.SetCID([16]byte{1, 2, 3}) },
+			func(p *DataPacket) interface{} { return p.CID() }, [16]byte{1, 2, 3}},
+		{"SourceName", func(p *DataPacket) { p.SetSourceName("source") },
+			func(p *DataPacket) interface{} { return p.SourceName() }, "source"},
+		{"Universe", func(p *DataPacket) { p.SetUniverse(0x1234) },
+			func(p *DataPacket) interface{} { return p.Universe() }, uint16(0x1234)},
+		{"Data", func(p *DataPacket) { p.SetData([]byte{1, 2, 3, 4}) },
+			func(p *DataPacket) interface{} { return string(p.Data()) }, string([]byte{1, 2, 3, 4})},
+		{"Priority", func(p *DataPacket) { p.SetPriority(42) },
+			func(p *DataPacket) interface{} { return p.Priority() }, byte(42)},
+		{"Sequence", func(p *DataPacket) { p.SetSequence(200) },
+			func(p *DataPacket) interface{} { return p.Sequence() }, byte(200)},
+		{"StreamTerminated", func(p *DataPacket) { p.SetStreamTerminated(true) },
+			func(p *DataPacket) interface{} { return p.StreamTerminated() }, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewDataPacket()
+			test.set(&p)
+			if o := test.get(&p); o != test.want {
+				t.Errorf("Wrong output! Was: %v; Should've been: %v", o, test.want)
+			}
+			//the value has to survive the serialization
+			raw, err := NewDataPacketRaw(p.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o := test.get(&raw); o != test.want {
+				t.Errorf("Wrong output after parsing! Was: %v; Should've been: %v", o, test.want)
+			}
+		})
+	}
+}
+
+func TestSequenceIncr(t *testing.T) {
+	p := NewDataPacket()
+	p.SetSequence(254)
+	for i := 0; i < 256; i++ {
+		shouldBe := byte((254 + i) % 256)
+		if p.Sequence() != shouldBe {
+			t.Fatalf("Wrong output! Was: %v; Should've been: %v", p.Sequence(), shouldBe)
+		}
+		p.SequenceIncr()
+	}
+}
+
+func TestNewDataPacketForUniverse(t *testing.T) {
+	cid := [16]byte{1, 2, 3}
+	p, err := NewDataPacketForUniverse(1, cid, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Universe() != 1 || p.CID() != cid || p.SourceName() != "test" || p.DmxStartCode() != 0 {
+		t.Errorf("Wrong output! Was: universe %v, CID %v, source name %v", p.Universe(), p.CID(), p.SourceName())
+	}
+	if errs := p.Validate(); len(errs) != 0 {
+		t.Errorf("The created packet had errors: %v", errs)
+	}
+	if _, err := NewDataPacketForUniverse(0, cid, "test"); err == nil {
+		t.Error("Err was nil! Should have been an error for universe 0!")
+	}
+	if _, err := NewDataPacketForUniverse(64000, cid, "test"); err == nil {
+		t.Error("Err was nil! Should have been an error for universe 64000!")
+	}
+	if _, err := NewDataPacketForUniverse(1, cid, string(make([]byte, 64))); err == nil {
+		t.Error("Err was nil! Should have been an error for a too long source name!")
+	}
+}
+
+func TestBytes(t *testing.T) {
 	p := NewDataPacket()
-	i := []byte{1, 2, 3, 4}
-	p.SetData(i)
-	if !bytes.Equal(i, p.Data()) {
-		t.Error("DMX data was not set or getted properly!")
+	p.SetCID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	p.SetSourceName("test")
+	p.SetUniverse(0x0102)
+	p.SetData([]byte{0xAA, 0xBB})
+
+	shouldBe := make([]byte, 128)
+	copy(shouldBe[0:16], []byte{0, 0x10, 0, 0, 'A', 'S', 'C', '-', 'E', '1', '.', '1', '7', 0, 0, 0})
+	copy(shouldBe[16:22], []byte{0x70, 112, 0, 0, 0, 4}) //root flags & length, vector
+	copy(shouldBe[22:38], []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	copy(shouldBe[38:44], []byte{0x70, 90, 0, 0, 0, 2}) //framing flags & length, vector
+	copy(shouldBe[44:48], "test")
+	shouldBe[108] = 100 //priority
+	copy(shouldBe[113:115], []byte{0x01, 0x02})
+	copy(shouldBe[115:117], []byte{0x70, 13}) //DMP flags & length
+	shouldBe[117] = 0x02
+	shouldBe[118] = 0xa1
+	shouldBe[122] = 0x01
+	copy(shouldBe[123:125], []byte{0, 3}) //property value count
+	copy(shouldBe[126:128], []byte{0xAA, 0xBB})
+
+	raw := p.Bytes()
+	if !bytes.Equal(raw, shouldBe) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", raw, shouldBe)
+	}
+	raw[126] = 0
+	if p.Data()[0] != 0xAA {
+		t.Error("Modifying the returned slice changed the packet!")
+	}
+}
+
+// BenchmarkSendPath measures the work that is done for every packet that is sent out
+func TestEqual(t *testing.T) {
+	newPacket := func() DataPacket {
+		p := NewDataPacket()
+		p.SetCID([16]byte{1})
+		p.SetUniverse(1)
+		p.SetPriority(100)
+		p.SetSequence(10)
+		p.SetData([]byte{1, 2, 3, 4})
+		return p
 	}
-	i = make([]byte, 600)
-	for j := range i {
-		i[j] = byte(rand.Uint32())
+	base := newPacket()
+	if other := newPacket(); !base.Equal(other) {
+		t.Error("Equal packets were not equal!")
+	}
+	tests := map[string]func(p *DataPacket){
+		"priority":   func(p *DataPacket) { p.SetPriority(101) },
+		"slot":       func(p *DataPacket) { p.SetData([]byte{1, 2, 3, 5}) },
+		"length":     func(p *DataPacket) { p.SetData([]byte{1, 2, 3, 4, 0, 0}) },
+		"sequence":   func(p *DataPacket) { p.SequenceIncr() },
+		"universe":   func(p *DataPacket) { p.SetUniverse(2) },
+		"cid":        func(p *DataPacket) { p.SetCID([16]byte{2}) },
+		"options":    func(p *DataPacket) { p.SetStreamTerminated(true) },
+		"start code": func(p *DataPacket) { p.SetDmxStartCode(0xDD) },
+		"name":       func(p *DataPacket) { p.SetSourceName("other") },
+	}
+	for name, change := range tests {
+		other := newPacket()
+		change(&other)
+		if base.Equal(other) || other.Equal(base) {
+			t.Errorf("Packets with different %v were equal!", name)
+		}
+	}
+}
+
+func TestDiffFrom(t *testing.T) {
+	prev, next := NewDataPacket(), NewDataPacket()
+	prev.SetData([]byte{1, 2, 3, 4})
+	next.SetData([]byte{1, 2, 3, 4})
+	if changes := next.DiffFrom(prev); changes != nil {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", changes, nil)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { next.DiffFrom(prev) }); allocs != 0 {
+		t.Errorf("DiffFrom allocated %v times for equal packets", allocs)
+	}
+
+	next.SetData([]byte{1, 9, 3, 4, 5, 0})
+	should := []SlotChange{{Slot: 1, OldValue: 2, NewValue: 9}, {Slot: 4, OldValue: 0, NewValue: 5}}
+	if changes := next.DiffFrom(prev); !reflect.DeepEqual(changes, should) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", changes, should)
+	}
+
+	//blackout to full on
+	prev.SetData(make([]byte, 512))
+	full := make([]byte, 512)
+	for i := range full {
+		full[i] = 255
+	}
+	next.SetData(full)
+	changes := next.DiffFrom(prev)
+	if len(changes) != 512 {
+		t.Fatalf("Wrong number of changes! Was: %v; Should've been: %v", len(changes), 512)
+	}
+	for i, change := range changes {
+		if change != (SlotChange{Slot: i, OldValue: 0, NewValue: 255}) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", change, SlotChange{Slot: i, OldValue: 0, NewValue: 255})
+		}
+	}
+}
+
+func BenchmarkSendPath(b *testing.B) {
+	p := NewDataPacket()
+	data := make([]byte, 512)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data[0] = byte(i)
+		p.SetData(data)
+		p.SequenceIncr()
+		sink = p.wireBytes()
+	}
+}
+
+var sink []byte
+
+func TestHash(t *testing.T) {
+	newPacket := func(universe uint16, cid [16]byte, prio byte, data []byte) DataPacket {
+		p := NewDataPacket()
+		p.SetUniverse(universe)
+		p.SetCID(cid)
+		p.SetPriority(prio)
+		p.SetData(data)
+		return p
+	}
+	p1 := newPacket(1, [16]byte{1}, 100, []byte{1, 2, 3, 4})
+	p2 := newPacket(1, [16]byte{1}, 100, []byte{1, 2, 3, 4})
+	p2.SetSequence(5) //the sequence is not part of the hash
+	if p1.Hash() != p2.Hash() || p1.ContentHash() != p2.ContentHash() {
+		t.Errorf("Equal packets have different hashes! Was: %v, %v; Should've been: %v, %v", p2.Hash(), p2.ContentHash(), p1.Hash(), p1.ContentHash())
+	}
+	for _, p := range []DataPacket{
+		newPacket(2, [16]byte{1}, 100, []byte{1, 2, 3, 4}),
+		newPacket(1, [16]byte{2}, 100, []byte{1, 2, 3, 4}),
+		newPacket(1, [16]byte{1}, 101, []byte{1, 2, 3, 4}),
+	} {
+		if p.Hash() == p1.Hash() {
+			t.Errorf("Different packets have the same hash: %v", p.Hash())
+		}
+		if p.ContentHash() != p1.ContentHash() {
+			t.Errorf("Wrong content hash! Was: %v; Should've been: %v", p.ContentHash(), p1.ContentHash())
+		}
+	}
+
+	//every single bit change of the data changes the content hash
+	data := make([]byte, 512)
+	rand.Read(data)
+	p := newPacket(1, [16]byte{1}, 100, data)
+	hashes := map[uint64]bool{p.ContentHash(): true}
+	for i := 0; i < len(data)*8; i++ {
+		changed := append([]byte(nil), data...)
+		changed[i/8] ^= 1 << uint(i%8)
+		c := newPacket(1, [16]byte{1}, 100, changed)
+		hashes[c.ContentHash()] = true
 	}
-	p.SetData(i)
-	if !bytes.Equal(i[0:512], p.Data()) {
-		t.Errorf("DMX data was not set or getted properly! Was: %v \nShouldbe: %v", p.Data(), i)
+	if len(hashes) != len(data)*8+1 {
+		t.Errorf("Wrong number of different content hashes! Was: %v; Should've been: %v", len(hashes), len(data)*8+1)
 	}
 }
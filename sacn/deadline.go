@@ -0,0 +1,45 @@
+package sacn
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetDeadline deactivates the universe at the given point in time, like closing its channel: the packets
+// with the stream terminated flag are sent. The channel is not closed, it still belongs to the caller and
+// frames that are written to it after the deadline are discarded. A deadline in the past deactivates the
+// universe immediately. A zero time clears the deadline of the universe.
+// Universes that belong to a UniverseGroup can not have a deadline.
+func (t *Transmitter) SetDeadline(universe uint16, deadline time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conn, ok := t.conns[universe]
+	if !ok {
+		return fmt.Errorf("the given universe %v is not activated", universe)
+	}
+	if _, ok := t.groups[universe]; ok {
+		return fmt.Errorf("the given universe %v belongs to a group", universe)
+	}
+	if timer, ok := t.deadlines[universe]; ok {
+		timer.Stop()
+		delete(t.deadlines, universe)
+	}
+	if deadline.IsZero() {
+		return nil
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(deadline), func() {
+		t.mu.Lock()
+		//the deadline may have been replaced or the universe deactivated in the meantime
+		current := t.deadlines[universe] == timer && t.conns[universe] == conn
+		if current {
+			delete(t.deadlines, universe)
+		}
+		t.mu.Unlock()
+		if current {
+			conn.deactivate()
+		}
+	})
+	t.deadlines[universe] = timer
+	return nil
+}
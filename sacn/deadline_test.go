@@ -0,0 +1,86 @@
+package sacn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetDeadline(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.SetMulticast(1, true)
+	if err := trans.SetDeadline(1, time.Now()); err == nil {
+		t.Error("Setting a deadline on a universe that is not activated did not return an error!")
+	}
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := trans.SetDeadline(1, start.Add(100*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !trans.IsActivated(1) {
+		t.Fatal("The universe was deactivated before the deadline!")
+	}
+	waitDeactivated(t, &trans, 1)
+	if took := time.Since(start); took < 100*time.Millisecond {
+		t.Errorf("The universe was deactivated after %v, before the deadline!", took)
+	}
+	//the channel still belongs to the caller
+	select {
+	case ch <- []byte{1}:
+	case <-time.After(time.Second):
+		t.Error("Writing to the channel after the deadline blocked!")
+	}
+	close(ch)
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	terminated := 0
+	for _, raw := range sender.packets {
+		p, err := NewDataPacketRaw(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.StreamTerminated() {
+			terminated++
+		}
+	}
+	if terminated != 3 {
+		t.Errorf("Wrong number of terminated packets! Was: %v; Should've been: %v", terminated, 3)
+	}
+}
+
+func TestClearDeadline(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	if err := trans.SetDeadline(1, time.Now().Add(50*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SetDeadline(1, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if !trans.IsActivated(1) {
+		t.Error("The universe was deactivated, although the deadline was cleared!")
+	}
+}
@@ -0,0 +1,31 @@
+package sacn
+
+import "fmt"
+
+// DimmerCurveStartCode is the start code of the ESTA dimmer curve selection packets. Instead of levels,
+// every slot of such a packet contains the index of the dimmer curve that is used for the channel.
+const DimmerCurveStartCode = 0x91
+
+// NewDimmerCurvePacket creates a packet with the start code 0x91 for the given universe, that selects
+// the dimmer curve of every channel: curves[0] is the curve index of channel 1 and so on. The packet can
+// be sent like any other DataPacket.
+func NewDimmerCurvePacket(universe uint16, cid [16]byte, curves [512]byte) DataPacket {
+	p := NewDataPacket()
+	p.SetUniverse(universe)
+	p.SetCID(cid)
+	p.SetDmxStartCode(DimmerCurveStartCode)
+	p.SetData(curves[:])
+	return p
+}
+
+// ParseDimmerCurvePacket returns the dimmer curve index of every channel from a packet with the start
+// code 0x91. Channels that are not contained in the packet get the index 0. An error is returned if the
+// packet has another start code.
+func ParseDimmerCurvePacket(dp DataPacket) ([512]byte, error) {
+	var curves [512]byte
+	if dp.DmxStartCode() != DimmerCurveStartCode {
+		return curves, fmt.Errorf("the start code was %#x and therefore is not the dimmer curve start code %#x", dp.DmxStartCode(), DimmerCurveStartCode)
+	}
+	copy(curves[:], dp.Data())
+	return curves, nil
+}
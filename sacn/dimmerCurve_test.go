@@ -0,0 +1,29 @@
+package sacn
+
+import "testing"
+
+func TestDimmerCurvePacket(t *testing.T) {
+	var curves [512]byte
+	for i := range curves {
+		curves[i] = byte(i % 7)
+	}
+	p := NewDimmerCurvePacket(5, [16]byte{1}, curves)
+	raw, err := NewDataPacketRaw(p.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.DmxStartCode() != DimmerCurveStartCode || raw.Universe() != 5 || raw.CID() != [16]byte{1} {
+		t.Errorf("Wrong packet! Start code: %#x; Universe: %v; CID: %v", raw.DmxStartCode(), raw.Universe(), raw.CID())
+	}
+	parsed, err := ParseDimmerCurvePacket(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != curves {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", parsed, curves)
+	}
+
+	if _, err := ParseDimmerCurvePacket(NewDataPacket()); err == nil {
+		t.Error("Err was nil! Should have been an error for a DMX packet!")
+	}
+}
@@ -0,0 +1,96 @@
+package sacn
+
+import (
+	"fmt"
+)
+
+const (
+	vectorRootE131Extended          = 8 //VECTOR_ROOT_E131_EXTENDED
+	vectorE131ExtendedDiscovery     = 2 //VECTOR_E131_EXTENDED_DISCOVERY
+	vectorUniverseDiscoveryUnivList = 1 //VECTOR_UNIVERSE_DISCOVERY_UNIVERSE_LIST
+	discoveryUniverse               = 64214
+	discoveryHeaderLength           = 120
+	discoveryMaxUniverses           = 512
+)
+
+// DiscoveryPacket holds the information of an E1.31 universe discovery packet. A source sends these
+// packets on the discovery universe 64214 to announce on which universes it is transmitting.
+// If a source transmits on more than 512 universes, the list is split into multiple pages.
+type DiscoveryPacket struct {
+	SourceCID  [16]byte
+	SourceName string
+	Page       byte
+	LastPage   byte
+	Universes  []uint16
+}
+
+// isDiscoveryPacket returns true if the root and framing layer vectors of the raw bytes
+// indicate a universe discovery packet
+func isDiscoveryPacket(raw []byte) bool {
+	return len(raw) >= 44 &&
+		getAsUint32(raw[18:22]) == vectorRootE131Extended &&
+		getAsUint32(raw[40:44]) == vectorE131ExtendedDiscovery
+}
+
+// ParseDiscoveryPacket parses the given raw bytes as a universe discovery packet.
+func ParseDiscoveryPacket(raw []byte) (DiscoveryPacket, error) {
+	var d DiscoveryPacket
+	if len(raw) < discoveryHeaderLength {
+		return d, fmt.Errorf("the given raw bytes are too short! Min length is %v was %v", discoveryHeaderLength, len(raw))
+	}
+	if !isDiscoveryPacket(raw) {
+		return d, fmt.Errorf("the given raw bytes are not a universe discovery packet")
+	}
+	if vector := getAsUint32(raw[114:118]); vector != vectorUniverseDiscoveryUnivList {
+		return d, fmt.Errorf("the universe discovery layer vector was %v and should have been %v", vector, vectorUniverseDiscoveryUnivList)
+	}
+	//the length of the universe discovery layer is stored in the lower 12 bits of the flags and length
+	length := int(getAsUint32(raw[112:114]) & 0x0FFF)
+	if length < 8 || 112+length > len(raw) || (length-8)%2 != 0 || (length-8)/2 > discoveryMaxUniverses {
+		return d, fmt.Errorf("the universe discovery layer length %v is not valid", length)
+	}
+	copy(d.SourceCID[:], raw[22:38])
+	name := raw[44:108]
+	i := 0
+	for i < len(name) && name[i] != 0 {
+		i++
+	}
+	d.SourceName = string(name[:i])
+	d.Page = raw[118]
+	d.LastPage = raw[119]
+	d.Universes = make([]uint16, 0, (length-8)/2)
+	for j := discoveryHeaderLength; j < 112+length; j += 2 {
+		d.Universes = append(d.Universes, uint16(getAsUint32(raw[j:j+2])))
+	}
+	return d, nil
+}
+
+// getBytes returns the packet in the wire format
+func (d *DiscoveryPacket) getBytes() []byte {
+	universes := d.Universes
+	if len(universes) > discoveryMaxUniverses {
+		universes = universes[:discoveryMaxUniverses]
+	}
+	length := uint16(discoveryHeaderLength + 2*len(universes))
+	raw := make([]byte, 0, length)
+	raw = append(raw, constHeader...)
+	fal := calculateFal(length - 16)
+	raw = append(raw, fal[:]...)
+	raw = append(raw, getAsBytes32(vectorRootE131Extended)...)
+	raw = append(raw, d.SourceCID[:]...)
+	fal = calculateFal(length - 38)
+	raw = append(raw, fal[:]...)
+	raw = append(raw, getAsBytes32(vectorE131ExtendedDiscovery)...)
+	name := [64]byte{}
+	copy(name[:63], d.SourceName)
+	raw = append(raw, name[:]...)
+	raw = append(raw, 0, 0, 0, 0) //reserved
+	fal = calculateFal(length - 112)
+	raw = append(raw, fal[:]...)
+	raw = append(raw, getAsBytes32(vectorUniverseDiscoveryUnivList)...)
+	raw = append(raw, d.Page, d.LastPage)
+	for _, univ := range universes {
+		raw = append(raw, getAsBytes16(univ)...)
+	}
+	return raw
+}
@@ -0,0 +1,58 @@
+package sacn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiscoveryPacket(t *testing.T) {
+	raw := []byte{0, 0x10, 0, 0, 0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00, //header
+		0x70, 0x6e, 0, 0, 0, 8, //root layer
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, //CID
+		0x70, 0x58, 0, 0, 0, 2} //framing layer
+	name := make([]byte, 64)
+	copy(name, "discovery source")
+	raw = append(raw, name...)
+	raw = append(raw, 0, 0, 0, 0, //reserved
+		0x70, 0x0e, 0, 0, 0, 1, //universe discovery layer
+		1, 2, //page and last page
+		0, 1, 0x01, 0x00, 0xf9, 0xff) //universes 1, 256 and 63999
+
+	d, err := ParseDiscoveryPacket(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shouldBe := DiscoveryPacket{
+		SourceCID:  [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SourceName: "discovery source",
+		Page:       1,
+		LastPage:   2,
+		Universes:  []uint16{1, 256, 63999},
+	}
+	if !reflect.DeepEqual(d, shouldBe) {
+		t.Errorf("Wrong output! Was: %+v; Should've been: %+v", d, shouldBe)
+	}
+	if out := shouldBe.getBytes(); !reflect.DeepEqual(out, raw) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, raw)
+	}
+
+	if _, err := ParseDiscoveryPacket(raw[:119]); err == nil {
+		t.Error("Err was nil! Should have been an error for a too short packet!")
+	}
+	if _, err := ParseDiscoveryPacket(raw[:124]); err == nil {
+		t.Error("Err was nil! Should have been an error for a truncated universe list!")
+	}
+}
+
+func TestListenDiscovery(t *testing.T) {
+	r := newReceiverSocket()
+	ch, err := r.ListenDiscovery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := DiscoveryPacket{SourceCID: [16]byte{1}, Universes: []uint16{1, 2}}
+	r.handleRaw(d.getBytes())
+	if out := <-ch; !reflect.DeepEqual(out.Universes, d.Universes) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out.Universes, d.Universes)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Package dmx provides helpers for building and comparing DMX frames, that can be sent via the sacn package.
+*/
+package dmx
+
+import (
+	"math"
+)
+
+// FrameSize is the number of slots in a full DMX frame.
+const FrameSize = 512
+
+// FloatToDMX converts an intensity in range [0.0-1.0] to a DMX value in range [0-255].
+// Values outside of the range are clamped, NaN is converted to 0.
+func FloatToDMX(f float64) byte {
+	if math.IsNaN(f) || f <= 0 {
+		return 0
+	}
+	if f >= 1 {
+		return 255
+	}
+	return byte(math.Round(f * 255))
+}
+
+// DMXToFloat converts a DMX value to an intensity in range [0.0-1.0].
+func DMXToFloat(b byte) float64 {
+	return float64(b) / 255
+}
+
+// ClampDMX clamps the value to the range of a DMX value [0-255].
+func ClampDMX(v int) byte {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return byte(v)
+}
+
+// BuildFrame returns a frame of 512 slots with the given values. The keys of the map are the DMX channel
+// numbers in range [1-512], so channel 1 is the first slot of the frame. Channels outside of the range
+// are ignored. All other slots are 0.
+func BuildFrame(channels map[int]byte) []byte {
+	frame := make([]byte, FrameSize)
+	for channel, value := range channels {
+		if channel < 1 || channel > FrameSize {
+			continue
+		}
+		frame[channel-1] = value
+	}
+	return frame
+}
+
+// Diff returns the indices of all slots where the two frames differ, in ascending order. The indices start
+// at 0. If the frames have different lengths, the slots that only exist in the longer frame are different.
+func Diff(a, b []byte) []int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	diff := make([]int, 0)
+	for i := range a {
+		if i >= len(b) || a[i] != b[i] {
+			diff = append(diff, i)
+		}
+	}
+	return diff
+}
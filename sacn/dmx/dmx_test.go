@@ -0,0 +1,62 @@
+package dmx
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFloatToDMX(t *testing.T) {
+	tests := map[float64]byte{-1: 0, 0: 0, 0.5: 128, 1: 255, 2: 255, math.NaN(): 0}
+	for in, shouldBe := range tests {
+		if out := FloatToDMX(in); out != shouldBe {
+			t.Errorf("Wrong output for %v! Was: %v; Should've been: %v", in, out, shouldBe)
+		}
+	}
+	//every DMX value has to survive a round trip
+	for i := 0; i < 256; i++ {
+		if out := FloatToDMX(DMXToFloat(byte(i))); out != byte(i) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", out, i)
+		}
+	}
+}
+
+func TestClampDMX(t *testing.T) {
+	for v := -300; v < 600; v++ {
+		shouldBe := v
+		if shouldBe < 0 {
+			shouldBe = 0
+		} else if shouldBe > 255 {
+			shouldBe = 255
+		}
+		if out := ClampDMX(v); int(out) != shouldBe {
+			t.Errorf("Wrong output for %v! Was: %v; Should've been: %v", v, out, shouldBe)
+		}
+	}
+}
+
+func TestBuildFrame(t *testing.T) {
+	frame := BuildFrame(map[int]byte{1: 10, 512: 20, 0: 30, 513: 40})
+	shouldBe := make([]byte, 512)
+	shouldBe[0], shouldBe[511] = 10, 20
+	if !reflect.DeepEqual(frame, shouldBe) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", frame, shouldBe)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b     []byte
+		shouldBe []int
+	}{
+		{[]byte{1, 2, 3}, []byte{1, 2, 3}, []int{}},
+		{[]byte{1, 2, 3}, []byte{0, 2, 4}, []int{0, 2}},
+		{[]byte{1}, []byte{1, 2, 3}, []int{1, 2}},
+		{nil, []byte{0}, []int{0}},
+	}
+	for _, test := range tests {
+		if out := Diff(test.a, test.b); !reflect.DeepEqual(out, test.shouldBe) {
+			t.Errorf("Wrong output for %v, %v! Was: %v; Should've been: %v", test.a, test.b, out, test.shouldBe)
+		}
+	}
+}
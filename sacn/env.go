@@ -0,0 +1,73 @@
+package sacn
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// NewTransmitterFromEnv creates a new Transmitter that is configured via environment variables. This is
+// useful for containerized deployments. The following variables are used:
+//
+//	SACN_SOURCE_NAME   the source name, required
+//	SACN_BIND_ADDRESS  the bind address, default ""
+//	SACN_CID           the CID as UUID string like "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", default is a random CID
+//	SACN_PRIORITY      the priority in range [1-200], default 100
+//	SACN_KEEPALIVE_MS  the keep alive interval in milliseconds in range [22-800], default 800
+//	SACN_PORT          the port for unicast destinations, default 5568
+//
+// Additional options can be provided.
+func NewTransmitterFromEnv(opts ...TransmitterOption) (Transmitter, error) {
+	sourceName, ok := os.LookupEnv("SACN_SOURCE_NAME")
+	if !ok {
+		return Transmitter{}, fmt.Errorf("the environment variable SACN_SOURCE_NAME is required")
+	}
+	var cid [16]byte
+	var err error
+	if value := os.Getenv("SACN_CID"); value != "" {
+		if cid, err = ParseCID(value); err != nil {
+			return Transmitter{}, fmt.Errorf("SACN_CID: %v", err)
+		}
+	} else if cid, err = NewRandomCID(); err != nil {
+		return Transmitter{}, err
+	}
+	priority, err := envInt("SACN_PRIORITY", 100, 1, 200)
+	if err != nil {
+		return Transmitter{}, err
+	}
+	keepAlive, err := envInt("SACN_KEEPALIVE_MS", 800, 22, 800)
+	if err != nil {
+		return Transmitter{}, err
+	}
+	port, err := envInt("SACN_PORT", 5568, 1, 65535)
+	if err != nil {
+		return Transmitter{}, err
+	}
+
+	t, err := NewTransmitter(os.Getenv("SACN_BIND_ADDRESS"), cid, sourceName, append([]TransmitterOption{WithPort(port)}, opts...)...)
+	if err != nil {
+		return t, err
+	}
+	t.SetPriority(byte(priority))
+	if err := t.SetKeepAlive(time.Duration(keepAlive) * time.Millisecond); err != nil {
+		return t, fmt.Errorf("SACN_KEEPALIVE_MS: %v", err)
+	}
+	return t, nil
+}
+
+// envInt reads the integer environment variable. If it is not set, the default is returned.
+func envInt(name string, def, min, max int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%v: %q is not a number", name, value)
+	}
+	if i < min || i > max {
+		return 0, fmt.Errorf("%v: %v is not in range [%v-%v]", name, i, min, max)
+	}
+	return i, nil
+}
@@ -0,0 +1,90 @@
+package sacn
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// setEnv sets the environment variables and returns a function that restores the old values
+func setEnv(t *testing.T, vars map[string]string) func() {
+	names := []string{"SACN_SOURCE_NAME", "SACN_BIND_ADDRESS", "SACN_CID", "SACN_PRIORITY", "SACN_KEEPALIVE_MS", "SACN_PORT"}
+	old := make(map[string]*string)
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			old[name] = &value
+		} else {
+			old[name] = nil
+		}
+		os.Unsetenv(name)
+	}
+	for name, value := range vars {
+		os.Setenv(name, value)
+	}
+	return func() {
+		for name, value := range old {
+			if value == nil {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, *value)
+			}
+		}
+	}
+}
+
+func TestNewTransmitterFromEnv(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		"SACN_SOURCE_NAME":  "env",
+		"SACN_CID":          "01020304-0506-0708-090a-0b0c0d0e0f10",
+		"SACN_PRIORITY":     "150",
+		"SACN_KEEPALIVE_MS": "500",
+		"SACN_PORT":         "6000",
+	})()
+	trans, err := NewTransmitterFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trans.cid != [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16} {
+		t.Errorf("Wrong CID! Was: %v", trans.cid)
+	}
+	if trans.sourceName != "env" || trans.priority != 150 || trans.keepAliveInterval != 500*time.Millisecond || trans.port != 6000 {
+		t.Errorf("Wrong output! Was: %v, %v, %v, %v", trans.sourceName, trans.priority, trans.keepAliveInterval, trans.port)
+	}
+}
+
+func TestNewTransmitterFromEnvDefaults(t *testing.T) {
+	defer setEnv(t, map[string]string{"SACN_SOURCE_NAME": "env"})()
+	first, err := NewTransmitterFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewTransmitterFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.cid == second.cid {
+		t.Error("The random CIDs are equal!")
+	}
+	if first.priority != 100 || first.port != 5568 {
+		t.Errorf("Wrong output! Was: %v, %v", first.priority, first.port)
+	}
+}
+
+func TestNewTransmitterFromEnvErrors(t *testing.T) {
+	tests := []map[string]string{
+		{},
+		{"SACN_SOURCE_NAME": "env", "SACN_CID": "invalid"},
+		{"SACN_SOURCE_NAME": "env", "SACN_PRIORITY": "201"},
+		{"SACN_SOURCE_NAME": "env", "SACN_PRIORITY": "0"}, //0 would silently send with the default priority
+		{"SACN_SOURCE_NAME": "env", "SACN_PRIORITY": "high"},
+		{"SACN_SOURCE_NAME": "env", "SACN_KEEPALIVE_MS": "0"},
+		{"SACN_SOURCE_NAME": "env", "SACN_PORT": "70000"},
+	}
+	for _, vars := range tests {
+		restore := setEnv(t, vars)
+		if _, err := NewTransmitterFromEnv(); err == nil {
+			t.Errorf("Err was nil for %v!", vars)
+		}
+		restore()
+	}
+}
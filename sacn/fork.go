@@ -0,0 +1,112 @@
+package sacn
+
+import (
+	"fmt"
+	"net"
+)
+
+// Fork creates a new Transmitter with the same configuration as this one and activates the given
+// universe on it. The fork uses the same CID, source name, priority, keep alive interval and
+// multicast options. The destinations with their priorities, the multicast setting and the current DMX
+// data of the universe are copied, so the fork starts sending out the last known data of this transmitter.
+// This is meant for hot-standby setups: use WithBindAddress to bind the fork to another network interface.
+// Changes on this transmitter after forking do not affect the fork. The universe has to be activated.
+// The channel of the forked universe can be obtained via Channel.
+func (t *Transmitter) Fork(universe uint16, opts ...TransmitterOption) (Transmitter, error) {
+	var data []byte
+	if !t.withMaster(universe, func(p *DataPacket) {
+		data = append([]byte(nil), p.Data()...)
+	}) {
+		return Transmitter{}, fmt.Errorf("the universe %v is not activated", universe)
+	}
+
+	fork, err := t.newWithSettings(t.bind, opts...)
+	if err != nil {
+		return fork, err
+	}
+	t.mu.RLock()
+	if name, ok := t.sourceNames[universe]; ok {
+		fork.sourceNames[universe] = name
+	}
+	if cid, ok := t.cids[universe]; ok {
+		fork.cids[universe] = cid
+	}
+	t.mu.RUnlock()
+	dests, prios := t.destinationSnapshot(universe)
+	fork.SetRawDestinations(universe, dests)
+	fork.destPriorities[universe] = prios
+	fork.SetMulticast(universe, t.IsMulticast(universe))
+
+	if len(data) == 0 {
+		_, err = fork.Activate(universe)
+	} else {
+		_, err = fork.ActivateWithData(universe, data)
+	}
+	return fork, err
+}
+
+// Clone creates a new Transmitter with the same configuration as this one, but bound to the given
+// address. This is meant for mirroring the output to a redundant network path. The clone uses the same
+// CID, source name, priority, keep alive interval and multicast options and gets a copy of the settings
+// of every universe: the multicast setting, the destinations with their priorities and the source name
+// and CID overrides. Callbacks like SetOnDataChange are not copied.
+// The clone starts without activated universes, they have to be activated by the caller.
+func (t *Transmitter) Clone(newBinding string) (Transmitter, error) {
+	clone, err := t.newWithSettings(newBinding)
+	if err != nil {
+		return clone, err
+	}
+	t.mu.RLock()
+	for univ, name := range t.sourceNames {
+		clone.sourceNames[univ] = name
+	}
+	for univ, cid := range t.cids {
+		clone.cids[univ] = cid
+	}
+	t.mu.RUnlock()
+	t.destMu.RLock()
+	defer t.destMu.RUnlock()
+	for univ, multicast := range t.multicast {
+		clone.multicast[univ] = multicast
+	}
+	for univ, dests := range t.destinations {
+		clone.destinations[univ] = append([]net.UDPAddr(nil), dests...)
+	}
+	for univ, prios := range t.destPriorities {
+		clone.destPriorities[univ] = make(map[string]byte, len(prios))
+		for dest, prio := range prios {
+			clone.destPriorities[univ][dest] = prio
+		}
+	}
+	return clone, nil
+}
+
+// newWithSettings creates a new Transmitter bound to the given address with the global settings of
+// this transmitter. The given options are applied after the copied settings.
+func (t *Transmitter) newWithSettings(bind string, opts ...TransmitterOption) (Transmitter, error) {
+	options := []TransmitterOption{
+		WithMulticastTTL(t.multicastTTL),
+		WithMulticastInterface(t.multicastIfi),
+		WithMaxFrameRate(t.maxFrameRate),
+		WithPort(t.port),
+		WithErrorChannel(t.errors),
+	}
+	if t.validate {
+		options = append(options, WithPacketValidation())
+	}
+	if t.limitPriority {
+		options = append(options, WithPriorityLimiter(t.maxPriority))
+	}
+	if t.rtp {
+		options = append(options, WithRTPTransport(t.rtpPayloadType))
+	}
+	n, err := NewTransmitter(bind, t.cid, t.sourceName, append(options, opts...)...)
+	if err != nil {
+		return n, err
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n.keepAliveInterval = t.keepAliveInterval
+	n.priority = t.priority
+	return n, nil
+}
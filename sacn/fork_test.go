@@ -0,0 +1,121 @@
+package sacn
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFork(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPriority(150)
+	ch, err := trans.ActivateWithData(1, []byte{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetRawDestinations(1, []net.UDPAddr{{IP: net.IPv4(127, 0, 0, 1), Port: port}})
+
+	fork, err := trans.Fork(1, WithBindAddress("127.0.0.1:0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch <- []byte{9, 9, 9, 9}
+	close(ch)
+	waitDeactivated(t, &trans, 1)
+
+	forkCh, ok := fork.Channel(1)
+	if !ok {
+		t.Fatal("Forked universe was not activated!")
+	}
+	defer close(forkCh)
+	if fork.bind != "127.0.0.1:0" {
+		t.Errorf("Wrong bind address! Was: %v; Should've been: %v", fork.bind, "127.0.0.1:0")
+	}
+	//drain all packets that were sent before the original was deactivated
+	buf := make([]byte, 638)
+	for {
+		conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		if _, _, err := conn.ReadFromUDP(buf); err != nil {
+			break
+		}
+	}
+	//now only the fork is sending
+	p := readTestPacket(t, conn)
+	if !bytes.Equal(p.Data(), []byte{1, 2, 3, 4}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), []byte{1, 2, 3, 4})
+	}
+	if p.CID() != [16]byte{1} || p.Priority() != 150 {
+		t.Errorf("Wrong output! Was: %v, %v; Should've been: %v, %v", p.CID(), p.Priority(), [16]byte{1}, 150)
+	}
+}
+
+func TestForkNotActivated(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.Fork(1); err == nil {
+		t.Error("Forking a universe that is not activated should fail!")
+	}
+}
+
+func TestClone(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithMulticastTTL(4), WithRTPTransport(97))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPriority(150)
+	trans.SetKeepAlive(500 * time.Millisecond)
+	trans.SetMulticast(1, true)
+	trans.SetDestinations(2, []string{"127.0.0.1", "127.0.0.2"})
+	dest := net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 5568}
+	if err := trans.SetPerDestinationPriority(2, dest, 50); err != nil {
+		t.Fatal(err)
+	}
+	trans.SetUniverseSourceName(3, "universe 3")
+	trans.SetUniverseCID(3, [16]byte{3})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+
+	clone, err := trans.Clone("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.bind != "127.0.0.1:0" {
+		t.Errorf("Wrong bind address! Was: %v; Should've been: %v", clone.bind, "127.0.0.1:0")
+	}
+	if clone.cid != trans.cid || clone.sourceName != trans.sourceName || clone.priority != 150 ||
+		clone.keepAliveInterval != 500*time.Millisecond || clone.multicastTTL != 4 ||
+		!clone.rtp || clone.rtpPayloadType != 97 {
+		t.Error("The global settings were not cloned!")
+	}
+	if len(clone.GetActivated()) != 0 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", clone.GetActivated(), []uint16{})
+	}
+	if !clone.IsMulticast(1) || clone.IsMulticast(2) {
+		t.Error("The multicast settings were not cloned!")
+	}
+	if dests := clone.Destinations(2); len(dests) != 2 || !dests[1].IP.Equal(dest.IP) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", dests, trans.Destinations(2))
+	}
+	if _, prios := clone.destinationSnapshot(2); prios[dest.String()] != 50 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", prios[dest.String()], 50)
+	}
+	if clone.sourceNames[3] != "universe 3" || clone.cids[3] != [16]byte{3} {
+		t.Error("The universe overrides were not cloned!")
+	}
+	//the clone must not share the destinations with the original
+	clone.SetDestinations(2, []string{"127.0.0.3"})
+	if dests := trans.Destinations(2); len(dests) != 2 {
+		t.Errorf("Changing the clone changed the original! Was: %v", dests)
+	}
+}
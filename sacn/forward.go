@@ -0,0 +1,157 @@
+package sacn
+
+import (
+	"fmt"
+	"sync"
+)
+
+// forwarder sends the packets of one received universe out on a universe of a transmitter
+type forwarder struct {
+	mu        sync.Mutex //held while sending, so the channel is never closed during a send
+	tx        *Transmitter
+	universe  uint16
+	ch        chan<- []byte
+	activated bool //true, if the universe was activated by Forward or Bridge
+	stopped   bool
+	bridge    bool                   //true, if created via Bridge. Then the merged data is used, if a Merger is set
+	transform func(in []byte) []byte //transforms the data of a bridge. nil for pass-through
+}
+
+// Forward sends the data that is received on srcUniverse out on dstUniverse of the transmitter, e.g. for
+// an sACN proxy. If dstUniverse is not activated on the transmitter, it gets activated by Forward.
+// Every packet that wins the priority and sequence checks of the receiver is written to the universe right
+// away in the goroutine of the receiver, so no extra buffering or delay is added. The priority of the
+// original source is used for the forwarded packets. If the source terminates its stream, the forwarding
+// stops and the universe is deactivated, if it was activated by Forward.
+// Call the returned function to stop the forwarding. The universe is deactivated in the same way.
+// A universe that was already activated must not be deactivated while it is forwarded.
+func (r *ReceiverSocket) Forward(srcUniverse uint16, tx *Transmitter, dstUniverse uint16) (func(), error) {
+	return r.addForwarder(srcUniverse, tx, dstUniverse, &forwarder{})
+}
+
+// Bridge works like Forward, but passes every frame through the transform function before it is sent,
+// e.g. for gamma correction or intensity scaling. The transform gets the frame that is delivered by
+// ListenDMX: if a Merger is set for srcUniverse, this is the merged data of all sources, otherwise the data
+// of the selected source. The returned frame is sent out, frames longer than 512 bytes are dropped by the
+// transmitter. The transform is called in the goroutine of the receiver and may modify the input frame.
+// A nil transform passes the frames through unchanged.
+func (r *ReceiverSocket) Bridge(srcUniverse uint16, tx *Transmitter, dstUniverse uint16, transform func(in []byte) []byte) (func(), error) {
+	return r.addForwarder(srcUniverse, tx, dstUniverse, &forwarder{bridge: true, transform: transform})
+}
+
+// addForwarder activates the universe for the forwarder, if necessary, and registers it
+func (r *ReceiverSocket) addForwarder(srcUniverse uint16, tx *Transmitter, dstUniverse uint16, f *forwarder) (func(), error) {
+	if err := checkListenUniverse(srcUniverse); err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("the transmitter is nil")
+	}
+	f.tx, f.universe = tx, dstUniverse
+	ch, ok := tx.Channel(dstUniverse)
+	if !ok {
+		var err error
+		if ch, err = tx.Activate(dstUniverse); err != nil {
+			return nil, err
+		}
+		f.activated = true
+	}
+	f.ch = ch
+	r.mu.Lock()
+	if r.forwarders == nil {
+		r.forwarders = make(map[uint16][]*forwarder)
+	}
+	r.forwarders[srcUniverse] = append(r.forwarders[srcUniverse], f)
+	r.mu.Unlock()
+	return func() {
+		r.removeForwarder(srcUniverse, f)
+		f.stop()
+	}, nil
+}
+
+// send writes the data to the universe. If withPrio is true, the priority is used for the universe.
+func (f *forwarder) send(data []byte, prio byte, withPrio bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return
+	}
+	if withPrio {
+		f.tx.withMaster(f.universe, func(p *DataPacket) {
+			p.SetPriority(prio)
+		})
+	}
+	data = append([]byte(nil), data...)
+	if f.transform != nil {
+		data = f.transform(data)
+	}
+	f.ch <- data
+}
+
+// stop stops the forwarding and deactivates the universe, if it was activated by Forward
+func (f *forwarder) stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return
+	}
+	f.stopped = true
+	if f.activated {
+		close(f.ch)
+	}
+}
+
+// removeForwarder removes the forwarder from the universe
+func (r *ReceiverSocket) removeForwarder(universe uint16, f *forwarder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.forwarders[universe]
+	for i, other := range list {
+		if other == f {
+			r.forwarders[universe] = append(list[:i:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(r.forwarders[universe]) == 0 {
+		delete(r.forwarders, universe)
+	}
+}
+
+// forward sends the packet to all forwarders of its universe. The lock must not be held, as sending
+// can block until the transmitter read the data.
+func (r *ReceiverSocket) forward(p DataPacket) {
+	r.mu.Lock()
+	list := r.forwarders[p.Universe()]
+	_, merged := r.mergers[p.Universe()]
+	r.mu.Unlock()
+	for _, f := range list {
+		if f.bridge && merged {
+			continue //the bridge gets the merged data, see forwardMerged
+		}
+		f.send(p.Data(), p.Priority(), true)
+	}
+}
+
+// forwardMerged sends the merged data to all bridges of the universe. The priority of the universe is
+// not changed, as the data belongs to multiple sources.
+func (r *ReceiverSocket) forwardMerged(universe uint16, merged []byte) {
+	r.mu.Lock()
+	list := r.forwarders[universe]
+	r.mu.Unlock()
+	for _, f := range list {
+		if f.bridge {
+			f.send(merged, 0, false)
+		}
+	}
+}
+
+// stopForwarding stops all forwarders of the universe, because its source terminated the stream
+func (r *ReceiverSocket) stopForwarding(universe uint16) {
+	r.mu.Lock()
+	list := r.forwarders[universe]
+	delete(r.forwarders, universe)
+	r.mu.Unlock()
+	for _, f := range list {
+		f.stop()
+	}
+}
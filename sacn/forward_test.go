@@ -0,0 +1,150 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// newForwardTransmitter creates a transmitter that records all packets instead of sending them
+func newForwardTransmitter(t *testing.T) (Transmitter, *recordingSender) {
+	trans, err := NewTransmitter("", [16]byte{1}, "proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	return trans, sender
+}
+
+// waitForwarded waits until a packet with the data was sent and returns it
+func waitForwarded(t *testing.T, sender *recordingSender, data []byte) DataPacket {
+	for i := 0; i < 100; i++ {
+		sender.mu.Lock()
+		for _, raw := range sender.packets {
+			if p, err := NewDataPacketRaw(raw); err == nil && bytes.Equal(p.Data(), data) {
+				sender.mu.Unlock()
+				return p
+			}
+		}
+		sender.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("The data %v was not forwarded!", data)
+	return DataPacket{}
+}
+
+func TestForward(t *testing.T) {
+	trans, sender := newForwardTransmitter(t)
+	trans.SetMulticast(7, true)
+	r := newReceiverSocket()
+	cancel, err := r.Forward(3, &trans, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	if !trans.IsActivated(7) {
+		t.Fatal("Forward did not activate the universe!")
+	}
+
+	p := newTestPacket(t, 3, [16]byte{5}, 150, []byte{1, 2})
+	r.handle(p)
+	out := waitForwarded(t, sender, []byte{1, 2})
+	if out.Universe() != 7 || out.Priority() != 150 || out.CID() != [16]byte{1} {
+		t.Errorf("Wrong packet! Universe: %v; Priority: %v; CID: %v", out.Universe(), out.Priority(), out.CID())
+	}
+	//packets of other universes are not forwarded
+	r.handle(newTestPacket(t, 4, [16]byte{5}, 150, []byte{3, 4}))
+
+	p.SetSequence(1)
+	p.SetStreamTerminated(true)
+	r.handle(p)
+	waitDeactivated(t, &trans, 7)
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	for _, raw := range sender.packets {
+		if p, _ := NewDataPacketRaw(raw); bytes.Equal(p.Data(), []byte{3, 4}) {
+			t.Error("A packet of another universe was forwarded!")
+		}
+	}
+}
+
+func TestForwardCancel(t *testing.T) {
+	trans, sender := newForwardTransmitter(t)
+	trans.SetMulticast(7, true)
+	trans.SetMulticast(8, true)
+	r := newReceiverSocket()
+	if _, err := r.Forward(0, &trans, 7); err == nil {
+		t.Error("Forwarding universe 0 did not return an error!")
+	}
+
+	cancel, err := r.Forward(3, &trans, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	waitDeactivated(t, &trans, 7)
+	cancel() //a second call does nothing
+
+	//a universe that was already activated stays activated
+	ch, err := trans.Activate(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	cancel, err = r.Forward(3, &trans, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.handle(newTestPacket(t, 3, [16]byte{5}, 100, []byte{9, 9}))
+	waitForwarded(t, sender, []byte{9, 9})
+	cancel()
+	next := newTestPacket(t, 3, [16]byte{5}, 100, []byte{8, 8})
+	next.SetSequence(1)
+	r.handle(next)
+	time.Sleep(20 * time.Millisecond)
+	if !trans.IsActivated(8) {
+		t.Error("Cancel deactivated a universe that was not activated by Forward!")
+	}
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	for _, raw := range sender.packets {
+		if p, _ := NewDataPacketRaw(raw); bytes.Equal(p.Data(), []byte{8, 8}) {
+			t.Error("Data was forwarded after cancel!")
+		}
+	}
+}
+
+func TestBridge(t *testing.T) {
+	trans, sender := newForwardTransmitter(t)
+	trans.SetMulticast(7, true)
+	trans.SetMulticast(8, true)
+	r := newReceiverSocket()
+	half := func(in []byte) []byte {
+		for i := range in {
+			in[i] /= 2
+		}
+		return in
+	}
+	cancel, err := r.Bridge(3, &trans, 7, half)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	r.handle(newTestPacket(t, 3, [16]byte{5}, 100, []byte{200, 101, 0, 255}))
+	waitForwarded(t, sender, []byte{100, 50, 0, 127})
+
+	//with a merger the merged data is transformed
+	r.SetMerger(4, HTPMerger{})
+	cancel, err = r.Bridge(4, &trans, 8, half)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	r.handle(newTestPacket(t, 4, [16]byte{1}, 100, []byte{20, 0}))
+	r.handle(newTestPacket(t, 4, [16]byte{2}, 100, []byte{0, 40}))
+	waitForwarded(t, sender, []byte{10, 20})
+}
@@ -1,9 +1,12 @@
 package sacn
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"net"
+	"strings"
 )
 
 //CalculateFal : Calculates the two bytes of a FlagsAndLength field of a sACN packet
@@ -34,6 +37,91 @@ func calcMulticastAddr(universe uint16) string {
 	return fmt.Sprintf("239.255.%v.%v", byt[0], byt[1])
 }
 
+// CalcMulticastAddr returns the IPv4 multicast address that is used for the given universe
+// (E1.31 9.3.1). The universe has to be in range [1-63999] or the discovery universe 64214.
+func CalcMulticastAddr(universe uint16) (string, error) {
+	if err := checkMulticastUniverse(universe); err != nil {
+		return "", err
+	}
+	return calcMulticastAddr(universe), nil
+}
+
+// CalcMulticastAddrV6 returns the IPv6 multicast address that is used for the given universe
+// (E1.31 9.3.2). The universe has to be in range [1-63999] or the discovery universe 64214.
+func CalcMulticastAddrV6(universe uint16) (string, error) {
+	if err := checkMulticastUniverse(universe); err != nil {
+		return "", err
+	}
+	byt := getAsBytes16(universe)
+	ip := net.IP{0xff, 0x18, 0, 0, 0, 0, 0, 0, 0, 0x83, 0, 0, 0, byt[0], 0, byt[1]}
+	return ip.String(), nil
+}
+
+// MulticastAddrs returns the IPv4 and IPv6 multicast addresses that are used for the given universe.
+func MulticastAddrs(universe uint16) (v4, v6 string, err error) {
+	if v4, err = CalcMulticastAddr(universe); err != nil {
+		return "", "", err
+	}
+	v6, err = CalcMulticastAddrV6(universe)
+	return v4, v6, err
+}
+
+// ExpectedMulticastGroups returns the IPv4 multicast groups that a receiver joins for the given universes.
+// The list is deduplicated and invalid universes are skipped. This can be used to plan the capacity of
+// the IGMP snooping tables of switches.
+func ExpectedMulticastGroups(universes []uint16) []net.IP {
+	groups := make([]net.IP, 0, len(universes))
+	seen := make(map[uint16]bool)
+	for _, universe := range universes {
+		if seen[universe] || checkMulticastUniverse(universe) != nil {
+			continue
+		}
+		seen[universe] = true
+		groups = append(groups, net.ParseIP(calcMulticastAddr(universe)))
+	}
+	return groups
+}
+
+// EstimateMulticastBandwidth returns the approximate bandwidth in bits per second that is needed for
+// sending the given universes via multicast with fps frames per second and dataSize DMX slots per frame.
+// The estimate includes the sACN, UDP and IPv4 headers, but not the headers of the link layer.
+func EstimateMulticastBandwidth(universes []uint16, fps float64, dataSize int) float64 {
+	packetSize := 126 + dataSize + 8 + 20 //sACN + UDP + IPv4
+	return float64(len(ExpectedMulticastGroups(universes))) * fps * float64(packetSize*8)
+}
+
+func checkMulticastUniverse(universe uint16) error {
+	if !IsValidDataUniverse(universe) && !IsDiscoveryUniverse(universe) {
+		return fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", universe)
+	}
+	return nil
+}
+
+// IsValidDataUniverse returns true if the universe can be used for DMX data, that is the range [1-63999].
+func IsValidDataUniverse(universe uint16) bool {
+	return universe >= 1 && universe <= 63999
+}
+
+// IsDiscoveryUniverse returns true if the universe is the E1.31 universe discovery universe 64214.
+func IsDiscoveryUniverse(universe uint16) bool {
+	return universe == discoveryUniverse
+}
+
+// UniverseDescription returns a human readable description of the universe for logging, like
+// "E1.31 universe 1", "E1.31 Universe Discovery" or "reserved (0)".
+func UniverseDescription(universe uint16) string {
+	switch {
+	case IsValidDataUniverse(universe):
+		return fmt.Sprintf("E1.31 universe %v", universe)
+	case IsDiscoveryUniverse(universe):
+		return "E1.31 Universe Discovery"
+	case universe == 0:
+		return "reserved (0)"
+	default:
+		return "reserved (>63999)"
+	}
+}
+
 func calcMulticastUDPAddr(universe uint16) *net.UDPAddr {
 	addr, _ := net.ResolveUDPAddr("udp", calcMulticastAddr(universe)+":5568")
 	return addr
@@ -47,3 +135,46 @@ func checkSequ(old, new byte) bool {
 	}
 	return true
 }
+
+// FormatCID formats the cid as lowercase UUID string like "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func FormatCID(cid [16]byte) string {
+	h := hex.EncodeToString(cid[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// NewRandomCID returns a new random CID, that is a version 4 UUID as recommended by E1.31.
+func NewRandomCID() ([16]byte, error) {
+	var cid [16]byte
+	if _, err := rand.Read(cid[:]); err != nil {
+		return cid, err
+	}
+	cid[6] = cid[6]&0x0F | 0x40 //UUID version 4
+	cid[8] = cid[8]&0x3F | 0x80 //UUID variant
+	return cid, nil
+}
+
+// ParseCID parses a UUID string. The formats "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", the same in braces
+// like "{xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx}" and 32 hex digits without hyphens are accepted.
+// Upper and lower case hex digits can be used.
+func ParseCID(s string) ([16]byte, error) {
+	var cid [16]byte
+	digits := s
+	if strings.HasPrefix(digits, "{") && strings.HasSuffix(digits, "}") {
+		digits = digits[1 : len(digits)-1]
+	}
+	if len(digits) == 36 {
+		if digits[8] != '-' || digits[13] != '-' || digits[18] != '-' || digits[23] != '-' {
+			return cid, fmt.Errorf("the cid %q is not a valid UUID string", s)
+		}
+		digits = digits[0:8] + digits[9:13] + digits[14:18] + digits[19:23] + digits[24:36]
+	} else if len(digits) != 32 || digits != s {
+		//braces are only allowed around the hyphenated format
+		return cid, fmt.Errorf("the cid %q is not a valid UUID string", s)
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return cid, fmt.Errorf("the cid %q is not a valid UUID string: %v", s, err)
+	}
+	copy(cid[:], b)
+	return cid, nil
+}
@@ -67,3 +67,122 @@ func TestCheckSequ(t *testing.T) {
 		t.Error("should not be allowed!")
 	}
 }
+
+func TestMulticastAddrs(t *testing.T) {
+	tests := []struct {
+		universe uint16
+		v4, v6   string
+	}{
+		{1, "239.255.0.1", "ff18::83:0:0:1"},
+		{100, "239.255.0.100", "ff18::83:0:0:64"},
+		{256, "239.255.1.0", "ff18::83:0:1:0"},
+		{63999, "239.255.249.255", "ff18::83:0:f9:ff"},
+		{64214, "239.255.250.214", "ff18::83:0:fa:d6"},
+	}
+	for _, test := range tests {
+		v4, v6, err := MulticastAddrs(test.universe)
+		if err != nil {
+			t.Error(err)
+		}
+		if v4 != test.v4 || v6 != test.v6 {
+			t.Errorf("Wrong output! Was: %v, %v; Should've been: %v, %v", v4, v6, test.v4, test.v6)
+		}
+	}
+	for _, invalid := range []uint16{0, 64000} {
+		if _, err := CalcMulticastAddr(invalid); err == nil {
+			t.Errorf("Err was nil! Should have been an error for universe %v!", invalid)
+		}
+		if _, err := CalcMulticastAddrV6(invalid); err == nil {
+			t.Errorf("Err was nil! Should have been an error for universe %v!", invalid)
+		}
+	}
+}
+
+func TestExpectedMulticastGroups(t *testing.T) {
+	groups := ExpectedMulticastGroups([]uint16{1, 2, 1, 256, 0})
+	shouldBe := []string{"239.255.0.1", "239.255.0.2", "239.255.1.0"}
+	if len(groups) != len(shouldBe) {
+		t.Fatalf("Wrong number of groups! Was: %v; Should've been: %v", len(groups), len(shouldBe))
+	}
+	for i, group := range groups {
+		if group.String() != shouldBe[i] {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", group, shouldBe[i])
+		}
+	}
+}
+
+func TestEstimateMulticastBandwidth(t *testing.T) {
+	//2 universes * 40 fps * (126+512+8+20 bytes) * 8 bits
+	if out := EstimateMulticastBandwidth([]uint16{1, 2}, 40, 512); out != 426240 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, 426240)
+	}
+}
+
+func TestUniverseDescription(t *testing.T) {
+	tests := []struct {
+		universe    uint16
+		description string
+		valid       bool
+		discovery   bool
+	}{
+		{0, "reserved (0)", false, false},
+		{1, "E1.31 universe 1", true, false},
+		{63999, "E1.31 universe 63999", true, false},
+		{64000, "reserved (>63999)", false, false},
+		{64213, "reserved (>63999)", false, false},
+		{64214, "E1.31 Universe Discovery", false, true},
+		{64215, "reserved (>63999)", false, false},
+		{65535, "reserved (>63999)", false, false},
+	}
+	for _, test := range tests {
+		if out := UniverseDescription(test.universe); out != test.description {
+			t.Errorf("Wrong output for %v! Was: %v; Should've been: %v", test.universe, out, test.description)
+		}
+		if out := IsValidDataUniverse(test.universe); out != test.valid {
+			t.Errorf("Wrong validity for %v! Was: %v; Should've been: %v", test.universe, out, test.valid)
+		}
+		if out := IsDiscoveryUniverse(test.universe); out != test.discovery {
+			t.Errorf("Wrong discovery for %v! Was: %v; Should've been: %v", test.universe, out, test.discovery)
+		}
+	}
+}
+
+func TestParseCID(t *testing.T) {
+	shouldBe := [16]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	formatted := "12345678-9abc-def0-0123-456789abcdef"
+	for _, s := range []string{
+		formatted,
+		"12345678-9ABC-DEF0-0123-456789ABCDEF",
+		"{12345678-9abc-def0-0123-456789abcdef}",
+		"123456789abcdef00123456789abcdef",
+	} {
+		cid, err := ParseCID(s)
+		if err != nil {
+			t.Errorf("Parsing %v: %v", s, err)
+			continue
+		}
+		if cid != shouldBe {
+			t.Errorf("Wrong output for %v! Was: %v; Should've been: %v", s, cid, shouldBe)
+		}
+		if out := FormatCID(cid); out != formatted {
+			t.Errorf("Wrong output for %v! Was: %v; Should've been: %v", s, out, formatted)
+		}
+		//formatting and parsing again has to give the same result
+		if again, err := ParseCID(FormatCID(cid)); err != nil || again != cid {
+			t.Errorf("Wrong output for %v! Was: %v; Should've been: %v", s, again, cid)
+		}
+	}
+	for _, invalid := range []string{
+		"",
+		"12345678-9abc-def0-0123-456789abcde",
+		"12345678-9abc-def0-0123-456789abcdeg",
+		"123456789-abc-def0-0123-456789abcdef",
+		"{12345678-9abc-def0-0123-456789abcdef",
+		"{123456789abcdef00123456789abcdef}",
+		"123456789abcdef00123456789abcde",
+	} {
+		if _, err := ParseCID(invalid); err == nil {
+			t.Errorf("Parsing %q did not return an error!", invalid)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+//go:build go1.18
+// +build go1.18
+
+package sacn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func FuzzParseDataPacket(f *testing.F) {
+	valid, _ := NewDataPacketForUniverse(1, [16]byte{1, 2, 3}, "test")
+	valid.SetData([]byte{1, 2, 3, 4})
+	f.Add(valid.Bytes())
+	full, _ := NewDataPacketForUniverse(63999, [16]byte{0xFF}, strings.Repeat("x", 63))
+	full.SetData(make([]byte, 512))
+	f.Add(full.Bytes())
+	f.Add(valid.Bytes()[:100])
+	f.Add(valid.Bytes()[:127])
+	options := valid.copy()
+	options.data[112] = 0xFF
+	f.Add(options.Bytes())
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		p, err := ParseDataPacket(raw)
+		if err != nil {
+			return
+		}
+		if errs := p.Validate(); len(errs) > 0 {
+			t.Fatalf("Parsed packet is not valid: %v", errs)
+		}
+		again, err := ParseDataPacket(p.Bytes())
+		if err != nil {
+			t.Fatalf("Serialized packet could not be parsed: %v", err)
+		}
+		if !bytes.Equal(again.Bytes(), p.Bytes()) {
+			t.Fatalf("Wrong output! Was: %v; Should've been: %v", again.Bytes(), p.Bytes())
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package sacn
+
+import (
+	"fmt"
+	"sync"
+)
+
+// UniverseGroup sends the same data on multiple universes. Create it with Transmitter.NewUniverseGroup.
+type UniverseGroup struct {
+	tx        *Transmitter
+	universes []uint16
+	ch        chan []byte
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewUniverseGroup activates all given universes and returns a group, whose channel broadcasts every
+// frame to all of these universes. If one of the universes could not be activated, the universes that
+// were already activated are deactivated again and an error is returned.
+// The universes are deactivated when the group is closed.
+func (t *Transmitter) NewUniverseGroup(universes []uint16) (*UniverseGroup, error) {
+	if len(universes) == 0 {
+		return nil, fmt.Errorf("a universe group needs at least one universe")
+	}
+	members := make([]chan<- []byte, 0, len(universes))
+	for _, univ := range universes {
+		member, err := t.Activate(univ)
+		if err != nil {
+			for _, m := range members {
+				close(m)
+			}
+			return nil, fmt.Errorf("could not activate universe %v of the group: %v", univ, err)
+		}
+		members = append(members, member)
+	}
+	g := &UniverseGroup{
+		tx:        t,
+		universes: append([]uint16(nil), universes...),
+		ch:        make(chan []byte),
+		done:      make(chan struct{}),
+	}
+	t.mu.Lock()
+	for _, univ := range universes {
+		t.groups[univ] = g
+	}
+	t.mu.Unlock()
+
+	go func() {
+		for data := range g.ch {
+			for _, m := range members {
+				m <- append([]byte(nil), data...)
+			}
+		}
+		for i, m := range members {
+			close(m)
+			t.mu.Lock()
+			delete(t.groups, g.universes[i])
+			t.mu.Unlock()
+		}
+		close(g.done)
+	}()
+	return g, nil
+}
+
+// Channel returns the channel, that broadcasts every frame to all universes of the group.
+// Do not close the channel, use Close instead.
+func (g *UniverseGroup) Channel() chan<- []byte {
+	return g.ch
+}
+
+// Universes returns the universes of the group.
+func (g *UniverseGroup) Universes() []uint16 {
+	return append([]uint16(nil), g.universes...)
+}
+
+// Close deactivates all universes of the group. It can be called multiple times.
+func (g *UniverseGroup) Close() {
+	g.closeOnce.Do(func() {
+		close(g.ch)
+	})
+	<-g.done
+}
+
+// Group returns the universe group the given universe belongs to, or nil if it is not part of a group.
+func (t *Transmitter) Group(universe uint16) *UniverseGroup {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.groups[universe]
+}
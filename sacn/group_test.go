@@ -0,0 +1,50 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestUniverseGroup(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour
+	universes := []uint16{1, 2, 3}
+	for _, univ := range universes {
+		trans.SetDestinationsWithPort(univ, []string{"127.0.0.1"}, port)
+	}
+	g, err := trans.NewUniverseGroup(universes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.NewUniverseGroup([]uint16{4, 2}); err == nil {
+		t.Error("Err was nil! Should have been an error for an already activated universe!")
+	}
+	waitDeactivated(t, &trans, 4)
+	if trans.Group(2) != g {
+		t.Error("Universe 2 is not tracked as member of the group!")
+	}
+
+	g.Channel() <- []byte{9, 9}
+	received := make(map[uint16]bool)
+	for len(received) < len(universes) {
+		p := readTestPacket(t, conn)
+		if bytes.Equal(p.Data(), []byte{9, 9}) {
+			received[p.Universe()] = true
+		}
+	}
+
+	g.Close()
+	g.Close()
+	for _, univ := range universes {
+		if trans.Group(univ) != nil {
+			t.Errorf("Universe %v is still tracked as group member!", univ)
+		}
+	}
+}
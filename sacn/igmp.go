@@ -0,0 +1,139 @@
+package sacn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"golang.org/x/net/ipv4"
+)
+
+// ErrNotSupported is returned if a feature can not be used on this platform or without the required
+// privileges.
+var ErrNotSupported = errors.New("sacn: not supported on this platform")
+
+// the multicast group IGMPv3 membership reports are sent to
+var igmpV3Reports = &net.IPAddr{IP: net.IPv4(224, 0, 0, 22)}
+
+// IGMP message types (RFC 1112, RFC 2236, RFC 3376)
+const (
+	igmpV1Report = 0x12
+	igmpV2Report = 0x16
+	igmpV3Report = 0x22
+)
+
+// igmpConn is the part of a raw IGMP socket that is used for listening for membership reports.
+// *ipv4.PacketConn implements this interface.
+type igmpConn interface {
+	multicastGroups
+	ReadFrom(b []byte) (int, *ipv4.ControlMessage, net.Addr, error)
+	Close() error
+}
+
+// listenIGMP opens a raw socket for IGMP. This requires raw socket privileges, e.g. root or
+// CAP_NET_RAW on Linux. If the socket can not be opened, ErrNotSupported is returned.
+func listenIGMP() (igmpConn, error) {
+	conn, err := net.ListenPacket("ip4:2", "0.0.0.0")
+	if err != nil {
+		return nil, ErrNotSupported
+	}
+	return ipv4.NewPacketConn(conn), nil
+}
+
+// ListenForReceivers reports the receivers that joined the multicast group of the universe. It listens
+// for the IGMP membership reports, that receivers send when they join a group and as answer to the
+// general queries of the IGMP querier in the network. Every receiver is reported once with the sACN
+// port. The universe has to be activated and the channel gets closed when the universe is deactivated.
+// This needs a raw socket and therefore special privileges (e.g. root or CAP_NET_RAW on Linux). If no
+// raw socket can be opened, ErrNotSupported is returned. Note that receivers behind IGMP snooping
+// switches may not be visible, as the switches only forward the reports to the querier.
+func (t *Transmitter) ListenForReceivers(universe uint16) (<-chan net.UDPAddr, error) {
+	t.mu.RLock()
+	done, ok := t.done[universe]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("the universe %v is not activated", universe)
+	}
+	conn, err := t.listenIGMP()
+	if err != nil {
+		return nil, err
+	}
+	group := generateMulticast(universe)
+	for _, addr := range []*net.IPAddr{{IP: group.IP}, igmpV3Reports} {
+		if err := conn.JoinGroup(t.multicastIfi, addr); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	ch := make(chan net.UDPAddr, listenerBufferSize)
+	go func() {
+		<-done
+		conn.Close()
+	}()
+	go readReceivers(conn, group.IP, ch)
+	return ch, nil
+}
+
+// readReceivers sends the address of every new member of the group to the channel, until reading from
+// the connection fails. Then the channel is closed.
+func readReceivers(conn igmpConn, group net.IP, ch chan<- net.UDPAddr) {
+	defer close(ch)
+	seen := make(map[string]bool)
+	buf := make([]byte, 1500)
+	for {
+		n, _, src, err := conn.ReadFrom(buf)
+		if err != nil {
+			return //the connection was closed
+		}
+		ipAddr, ok := src.(*net.IPAddr)
+		if !ok || seen[ipAddr.IP.String()] {
+			continue
+		}
+		for _, joined := range parseIGMPReport(buf[:n]) {
+			if joined.Equal(group) {
+				seen[ipAddr.IP.String()] = true
+				ch <- net.UDPAddr{IP: ipAddr.IP, Port: 5568}
+				break
+			}
+		}
+	}
+}
+
+// parseIGMPReport returns the groups that are joined by an IGMP membership report. Leave messages and
+// all other messages return no groups. An IPv4 header in front of the message is skipped.
+func parseIGMPReport(b []byte) []net.IP {
+	if len(b) > 0 && b[0]>>4 == 4 {
+		headerLen := int(b[0]&0x0f) * 4
+		if len(b) < headerLen {
+			return nil
+		}
+		b = b[headerLen:]
+	}
+	if len(b) < 8 {
+		return nil
+	}
+	switch b[0] {
+	case igmpV1Report, igmpV2Report:
+		return []net.IP{net.IP(append([]byte(nil), b[4:8]...))}
+	case igmpV3Report:
+		groups := make([]net.IP, 0)
+		records := int(binary.BigEndian.Uint16(b[6:8]))
+		b = b[8:]
+		for i := 0; i < records && len(b) >= 8; i++ {
+			recordType, auxLen, sources := b[0], int(b[1]), int(binary.BigEndian.Uint16(b[2:4]))
+			group := net.IP(append([]byte(nil), b[4:8]...))
+			//mode is exclude (2, 4) or sources are explicitly included (1, 5)
+			if recordType == 2 || recordType == 4 || ((recordType == 1 || recordType == 5) && sources > 0) {
+				groups = append(groups, group)
+			}
+			size := 8 + 4*sources + 4*auxLen
+			if len(b) < size {
+				break
+			}
+			b = b[size:]
+		}
+		return groups
+	}
+	return nil
+}
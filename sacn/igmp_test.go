@@ -0,0 +1,145 @@
+package sacn
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+// mockIGMPConn delivers the given IGMP messages and blocks afterwards until it is closed
+type mockIGMPConn struct {
+	mockGroups
+	messages chan mockIGMPMessage
+	closed   chan struct{}
+}
+
+type mockIGMPMessage struct {
+	src  string
+	data []byte
+}
+
+func newMockIGMPConn(messages ...mockIGMPMessage) *mockIGMPConn {
+	m := &mockIGMPConn{
+		messages: make(chan mockIGMPMessage, len(messages)),
+		closed:   make(chan struct{}),
+	}
+	for _, msg := range messages {
+		m.messages <- msg
+	}
+	return m
+}
+
+func (m *mockIGMPConn) ReadFrom(b []byte) (int, *ipv4.ControlMessage, net.Addr, error) {
+	select {
+	case msg := <-m.messages:
+		return copy(b, msg.data), nil, &net.IPAddr{IP: net.ParseIP(msg.src)}, nil
+	case <-m.closed:
+		return 0, nil, nil, io.EOF
+	}
+}
+
+func (m *mockIGMPConn) Close() error {
+	close(m.closed)
+	return nil
+}
+
+func TestParseIGMPReport(t *testing.T) {
+	v2 := []byte{igmpV2Report, 0, 0, 0, 239, 255, 0, 1}
+	withHeader := append(make([]byte, 20), v2...)
+	withHeader[0] = 0x45
+	v3 := []byte{igmpV3Report, 0, 0, 0, 0, 0, 0, 3,
+		4, 0, 0, 0, 239, 255, 0, 2, //change to exclude: join
+		3, 0, 0, 0, 239, 255, 0, 3, //change to include without sources: leave
+		1, 1, 0, 1, 239, 255, 0, 4, 10, 0, 0, 1, 0, 0, 0, 0, //include one source with aux data
+	}
+	tests := []struct {
+		name   string
+		data   []byte
+		should []net.IP
+	}{
+		{"v2", v2, []net.IP{net.IPv4(239, 255, 0, 1).To4()}},
+		{"ip header", withHeader, []net.IP{net.IPv4(239, 255, 0, 1).To4()}},
+		{"v3", v3, []net.IP{net.IPv4(239, 255, 0, 2).To4(), net.IPv4(239, 255, 0, 4).To4()}},
+		{"leave", []byte{0x17, 0, 0, 0, 239, 255, 0, 1}, nil},
+		{"short", []byte{igmpV2Report, 0}, nil},
+	}
+	for _, tt := range tests {
+		if got := parseIGMPReport(tt.data); !reflect.DeepEqual(got, tt.should) {
+			t.Errorf("%v: Wrong output! Was: %v; Should've been: %v", tt.name, got, tt.should)
+		}
+	}
+}
+
+func TestListenForReceivers(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	conn := newMockIGMPConn(
+		mockIGMPMessage{"10.0.0.1", []byte{igmpV2Report, 0, 0, 0, 239, 255, 0, 1}},
+		mockIGMPMessage{"10.0.0.2", []byte{igmpV2Report, 0, 0, 0, 239, 255, 0, 2}}, //another universe
+		mockIGMPMessage{"10.0.0.1", []byte{igmpV2Report, 0, 0, 0, 239, 255, 0, 1}}, //already reported
+		mockIGMPMessage{"10.0.0.3", []byte{igmpV3Report, 0, 0, 0, 0, 0, 0, 1, 2, 0, 0, 0, 239, 255, 0, 1}},
+	)
+	trans.listenIGMP = func() (igmpConn, error) {
+		return conn, nil
+	}
+	if _, err := trans.ListenForReceivers(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a not activated universe!")
+	}
+
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receivers, err := trans.ListenForReceivers(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should := []string{"239.255.0.1", "224.0.0.22"}; !reflect.DeepEqual(conn.joined, should) {
+		t.Errorf("Wrong groups! Was: %v; Should've been: %v", conn.joined, should)
+	}
+	for _, should := range []string{"10.0.0.1:5568", "10.0.0.3:5568"} {
+		if addr := <-receivers; addr.String() != should {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", addr.String(), should)
+		}
+	}
+	close(ch)
+	select {
+	case addr, ok := <-receivers:
+		if ok {
+			t.Errorf("Unexpected receiver: %v", addr.String())
+		}
+	case <-time.After(time.Second):
+		t.Error("The channel was not closed after the universe was deactivated!")
+	}
+}
+
+func TestListenForReceiversNotSupported(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.listenIGMP = func() (igmpConn, error) {
+		return nil, ErrNotSupported
+	}
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	if _, err := trans.ListenForReceivers(1); err != ErrNotSupported {
+		t.Errorf("Wrong error! Was: %v; Should've been: %v", err, ErrNotSupported)
+	}
+}
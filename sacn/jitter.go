@@ -0,0 +1,87 @@
+package sacn
+
+import "time"
+
+// SetJitterBudget makes the keep alive packets of the universe follow a fixed grid of multiples of the
+// keep alive interval on the wall clock, for networks that expect a bounded jitter like AVB/TSN. In
+// contrast to the default, the delays do not add up over time. If the time between two keep alive
+// packets exceeds the interval plus the jitter, the callback of SetOnJitterViolation is called.
+// The keep alive interval is read when the grid is started, so SetKeepAlive should be called before.
+// Use a negative jitter to return to the default timing.
+func (t *Transmitter) SetJitterBudget(universe uint16, jitter time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if jitter < 0 {
+		delete(t.jitterBudgets, universe)
+		return
+	}
+	t.jitterBudgets[universe] = jitter
+}
+
+// SetOnJitterViolation sets a callback that is called if the time between two keep alive packets of a
+// universe with a jitter budget exceeds the budget, see SetJitterBudget. It gets the actual time between
+// the packets. The callback is called in the goroutine that sends the keep alive packets, so it should
+// return quickly. Use nil to remove the callback.
+func (t *Transmitter) SetOnJitterViolation(fn func(universe uint16, actual time.Duration)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onJitterViolation = fn
+}
+
+// keepAliveTimer holds the state of the keep alive timing of one universe
+type keepAliveTimer struct {
+	ticker   *time.Ticker //not nil, if the grid timing is used
+	interval time.Duration
+	last     time.Time //the time of the last tick
+}
+
+// stop stops the ticker of the grid timing
+func (k *keepAliveTimer) stop() {
+	if k.ticker != nil {
+		k.ticker.Stop()
+		k.ticker = nil
+	}
+}
+
+// waitKeepAlive blocks until the next keep alive packet of the universe has to be sent or done is closed
+func (t *Transmitter) waitKeepAlive(universe uint16, k *keepAliveTimer, done <-chan struct{}) {
+	t.mu.RLock()
+	budget, ok := t.jitterBudgets[universe]
+	onViolation := t.onJitterViolation
+	interval := t.keepAliveInterval
+	t.mu.RUnlock()
+	if !ok {
+		k.stop()
+		sleep(interval, done)
+		return
+	}
+	if k.ticker == nil {
+		//align the first tick to the grid. The ticker uses the monotonic clock, so it does not drift
+		k.interval = interval
+		now := time.Now()
+		sleep(now.Truncate(k.interval).Add(k.interval).Sub(now), done)
+		k.ticker = time.NewTicker(k.interval)
+		k.last = time.Now()
+		return
+	}
+	select {
+	case <-k.ticker.C:
+	case <-done:
+		return
+	}
+	now := time.Now()
+	if actual := now.Sub(k.last); actual > k.interval+budget && onViolation != nil {
+		onViolation(universe, actual)
+	}
+	k.last = now
+}
+
+// sleep blocks for the duration or until done is closed
+func sleep(d time.Duration, done <-chan struct{}) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-done:
+	}
+}
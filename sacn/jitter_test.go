@@ -0,0 +1,67 @@
+package sacn
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetJitterBudget(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	const interval = 50 * time.Millisecond
+	const budget = 10 * time.Millisecond
+	if err := trans.SetKeepAlive(interval); err != nil {
+		t.Fatal(err)
+	}
+	trans.SetMulticast(1, true)
+	trans.SetJitterBudget(1, budget)
+	var mu sync.Mutex
+	var sent []time.Time
+	trans.SetPacketInterceptor(func(p *DataPacket) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, time.Now())
+		if len(sent) == 8 {
+			time.Sleep(3 * interval) //a delayed packet violates the budget
+		}
+		return true
+	})
+	violations := make(chan time.Duration, 10)
+	trans.SetOnJitterViolation(func(universe uint16, actual time.Duration) {
+		violations <- actual
+	})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * interval)
+	close(ch)
+	waitDeactivated(t, &trans, 1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	//the first packet is sent immediately, the second one on the grid
+	if offset := sent[1].Sub(sent[1].Truncate(interval)); offset > 20*time.Millisecond {
+		t.Errorf("The keep alive packets are not aligned to the grid! Offset: %v", offset)
+	}
+	for i := 2; i < 8; i++ {
+		//generous tolerance for loaded test machines
+		if gap := sent[i].Sub(sent[i-1]); gap < interval-budget-20*time.Millisecond || gap > interval+budget+20*time.Millisecond {
+			t.Errorf("Wrong interval between packet %v and %v! Was: %v; Should've been: %v", i-1, i, gap, interval)
+		}
+	}
+	select {
+	case actual := <-violations:
+		if actual <= interval+budget {
+			t.Errorf("Wrong violation! Was: %v; Should've been more than: %v", actual, interval+budget)
+		}
+	default:
+		t.Error("The jitter violation was not reported!")
+	}
+}
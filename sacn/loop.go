@@ -0,0 +1,127 @@
+package sacn
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// frameLoop sends a sequence of frames round-robin, see ActivateLoop
+type frameLoop struct {
+	mu       sync.Mutex
+	frames   [][]byte
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// ActivateLoop activates the universe and sends the frames round-robin with the given rate in frames per
+// second, e.g. for a chase or a pre-computed cue. The first frame is sent immediately. The frames can be
+// replaced via UpdateLoopFrames while the loop is running. Call the returned function to stop the loop
+// and deactivate the universe, like closing its channel. The channel of the universe is owned by the loop,
+// so the universe must not be deactivated otherwise.
+// Every frame has to be 0 to 512 bytes long and at least one frame has to be given.
+func (t *Transmitter) ActivateLoop(universe uint16, frames [][]byte, fps float64) (func(), error) {
+	if fps <= 0 {
+		return nil, fmt.Errorf("the frame rate was %v and has to be greater than 0", fps)
+	}
+	if err := checkLoopFrames(frames); err != nil {
+		return nil, err
+	}
+	loop := &frameLoop{
+		frames: copyFrames(frames),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	//the loop is registered together with the activation, so a concurrent CloseWithTimeout or
+	//Deactivate always sees it
+	t.mu.Lock()
+	ch, err := t.activateLocked(universe, make([]byte, 512), 0)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, err
+	}
+	t.loops[universe] = loop
+	t.mu.Unlock()
+
+	go func() {
+		defer close(loop.done)
+		defer close(ch)
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / fps))
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			loop.mu.Lock()
+			frame := loop.frames[i%len(loop.frames)]
+			loop.mu.Unlock()
+			select {
+			case ch <- frame:
+			case <-loop.stop:
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-loop.stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		t.mu.Lock()
+		if t.loops[universe] == loop {
+			delete(t.loops, universe)
+		}
+		t.mu.Unlock()
+		loop.halt()
+	}, nil
+}
+
+// halt stops the loop and waits until the loop closed the channel of its universe. It can be called
+// multiple times.
+func (l *frameLoop) halt() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+	<-l.done
+}
+
+// UpdateLoopFrames replaces the frames of the loop of the universe, that was started via ActivateLoop.
+// The loop continues with the next position in the new frames. Every frame has to be 0 to 512 bytes long
+// and at least one frame has to be given.
+func (t *Transmitter) UpdateLoopFrames(universe uint16, frames [][]byte) error {
+	if err := checkLoopFrames(frames); err != nil {
+		return err
+	}
+	t.mu.RLock()
+	loop, ok := t.loops[universe]
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("the universe %v has no running loop", universe)
+	}
+	loop.mu.Lock()
+	defer loop.mu.Unlock()
+	loop.frames = copyFrames(frames)
+	return nil
+}
+
+// checkLoopFrames returns an error if no frames are given or one of the frames is too long
+func checkLoopFrames(frames [][]byte) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("at least one frame has to be given")
+	}
+	for i, frame := range frames {
+		if len(frame) > 512 {
+			return fmt.Errorf("frame %v was %v bytes long and therefore longer than 512 bytes", i, len(frame))
+		}
+	}
+	return nil
+}
+
+// copyFrames returns a deep copy of the frames, so the caller can reuse the slices
+func copyFrames(frames [][]byte) [][]byte {
+	copied := make([][]byte, len(frames))
+	for i, frame := range frames {
+		copied[i] = append([]byte(nil), frame...)
+	}
+	return copied
+}
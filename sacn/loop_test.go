@@ -0,0 +1,115 @@
+package sacn
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestActivateLoop(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetMulticast(1, true)
+	if _, err := trans.ActivateLoop(1, nil, 50); err == nil {
+		t.Error("Err was nil! Should have been an error for no frames!")
+	}
+	if _, err := trans.ActivateLoop(1, [][]byte{{1}}, 0); err == nil {
+		t.Error("Err was nil! Should have been an error for 0 fps!")
+	}
+	if err := trans.UpdateLoopFrames(1, [][]byte{{1}}); err == nil {
+		t.Error("Err was nil! Should have been an error for a universe without loop!")
+	}
+
+	const fps = 50
+	stop, err := trans.ActivateLoop(1, [][]byte{{1, 0}, {2, 0}, {3, 0}}, fps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if err := trans.UpdateLoopFrames(1, [][]byte{{9, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	stop()
+	waitDeactivated(t, &trans, 1)
+	stop() //a second call does nothing
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	var values []byte
+	for _, raw := range sender.packets {
+		p, err := NewDataPacketRaw(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.StreamTerminated() || len(p.Data()) != 2 {
+			continue //keep alive and termination
+		}
+		values = append(values, p.Data()[0])
+	}
+	//the first keep alive packet may already contain the first frame
+	if len(values) > 1 && values[0] == 1 && values[1] == 1 {
+		values = values[1:]
+	}
+	//0.6s with 50fps and the first frame is sent immediately
+	if len(values) < 27 || len(values) > 35 {
+		t.Errorf("Wrong number of frames! Was: %v; Should've been about %v", len(values), 31)
+	}
+	updated := false
+	for i, value := range values {
+		if value == 9 {
+			updated = true
+			continue
+		}
+		if updated {
+			t.Fatalf("An old frame was sent after the update: %v", values)
+		}
+		if should := byte(i%3 + 1); value != should {
+			t.Fatalf("Wrong order! Was: %v; Should've been: %v at %v", value, should, i)
+		}
+	}
+	if !updated {
+		t.Error("The updated frames were not sent!")
+	}
+	if p, _ := NewDataPacketRaw(sender.packets[len(sender.packets)-1]); !p.StreamTerminated() {
+		t.Error("The loop was not terminated with the stream terminated flag!")
+	}
+}
+
+func TestActivateLoopConcurrentClose(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for univ := uint16(1); univ <= 50; univ++ {
+			trans.ActivateLoop(univ, [][]byte{{1}}, 1000)
+		}
+	}()
+	trans.CloseWithTimeout(context.Background())
+	<-done
+	//every loop that was started is stopped by Close
+	closed := make(chan struct{})
+	go func() {
+		trans.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return!")
+	}
+}
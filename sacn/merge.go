@@ -0,0 +1,77 @@
+package sacn
+
+import (
+	"time"
+)
+
+// SourceFrame is the latest DMX frame of one source on a universe, that is passed to a Merger.
+type SourceFrame struct {
+	CID         [16]byte
+	Priority    byte
+	Data        []byte
+	ArrivalTime time.Time
+}
+
+// Merger merges the frames of all sources that are sending on a universe into one frame.
+// Merge is called with at least one frame and must not modify the frames.
+type Merger interface {
+	Merge(sources []SourceFrame) []byte
+}
+
+// HTPMerger merges with "highest takes precedence": every slot gets the highest value of all sources,
+// regardless of their priority.
+type HTPMerger struct{}
+
+// Merge implements the Merger interface.
+func (HTPMerger) Merge(sources []SourceFrame) []byte {
+	out := make([]byte, maxFrameLength(sources))
+	for _, source := range sources {
+		for i, value := range source.Data {
+			if value > out[i] {
+				out[i] = value
+			}
+		}
+	}
+	return out
+}
+
+// LTPMerger merges with "latest takes precedence": the frame of the source that sent last is used,
+// regardless of the priority.
+type LTPMerger struct{}
+
+// Merge implements the Merger interface.
+func (LTPMerger) Merge(sources []SourceFrame) []byte {
+	latest := sources[0]
+	for _, source := range sources[1:] {
+		if source.ArrivalTime.After(latest.ArrivalTime) {
+			latest = source
+		}
+	}
+	return append([]byte(nil), latest.Data...)
+}
+
+// HighestPriorityMerger uses the frame of the source with the highest priority. If multiple sources
+// have the highest priority, the frame that arrived last is used.
+type HighestPriorityMerger struct{}
+
+// Merge implements the Merger interface.
+func (HighestPriorityMerger) Merge(sources []SourceFrame) []byte {
+	best := sources[0]
+	for _, source := range sources[1:] {
+		if source.Priority > best.Priority ||
+			(source.Priority == best.Priority && source.ArrivalTime.After(best.ArrivalTime)) {
+			best = source
+		}
+	}
+	return append([]byte(nil), best.Data...)
+}
+
+func maxFrameLength(sources []SourceFrame) int {
+	length := 0
+	for _, source := range sources {
+		if len(source.Data) > length {
+			length = len(source.Data)
+		}
+	}
+	return length
+}
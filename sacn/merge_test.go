@@ -0,0 +1,42 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestMergers(t *testing.T) {
+	now := time.Now()
+	sources := []SourceFrame{
+		{CID: [16]byte{1}, Priority: 150, Data: []byte{10, 200, 30}, ArrivalTime: now},
+		{CID: [16]byte{2}, Priority: 100, Data: []byte{100, 20}, ArrivalTime: now.Add(time.Millisecond)},
+	}
+	tests := []struct {
+		name   string
+		merger Merger
+		want   []byte
+	}{
+		{"HTP", HTPMerger{}, []byte{100, 200, 30}},
+		{"LTP", LTPMerger{}, []byte{100, 20}},
+		{"HighestPriority", HighestPriorityMerger{}, []byte{10, 200, 30}},
+	}
+	for _, test := range tests {
+		if out := test.merger.Merge(sources); !bytes.Equal(out, test.want) {
+			t.Errorf("%v: Wrong output! Was: %v; Should've been: %v", test.name, out, test.want)
+		}
+	}
+}
+
+func TestSetMerger(t *testing.T) {
+	r := newReceiverSocket()
+	r.SetMerger(1, HTPMerger{})
+	dmx, _ := r.ListenDMX(1)
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 150, []byte{10, 200}))
+	r.handle(newTestPacket(t, 1, [16]byte{2}, 100, []byte{100, 20}))
+
+	<-dmx
+	if out := <-dmx; !bytes.Equal(out, []byte{100, 200}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{100, 200})
+	}
+}
@@ -0,0 +1,38 @@
+package sacn
+
+import "fmt"
+
+// RDMnetStartCode is the start code of packets that carry RDMnet messages instead of DMX levels.
+const RDMnetStartCode = 0xCC
+
+// EncodeRDMnet creates a packet with the start code 0xCC for the given universe, that carries the
+// RDMnet message as payload. The preview data and stream terminated flags are cleared. In contrast to
+// SetData, a payload with an odd length is not padded, so DecodeRDMnet returns exactly the given payload.
+// An error is returned if the universe is invalid or the payload is longer than 512 bytes.
+func EncodeRDMnet(universe uint16, cid [16]byte, sourceName string, rdmnetPayload []byte) (DataPacket, error) {
+	p := NewDataPacket()
+	if !IsValidDataUniverse(universe) {
+		return p, fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", universe)
+	}
+	if len(rdmnetPayload) > 512 {
+		return p, fmt.Errorf("the payload length was %v and therefore is not in range [0-512]", len(rdmnetPayload))
+	}
+	p.SetUniverse(universe)
+	p.SetCID(cid)
+	p.SetSourceName(sourceName)
+	p.SetDmxStartCode(RDMnetStartCode)
+	p.SetPreviewData(false)
+	p.SetStreamTerminated(false)
+	p.replace(126, rdmnetPayload)
+	p.setFAL(uint16(126 + len(rdmnetPayload)))
+	return p, nil
+}
+
+// DecodeRDMnet returns a copy of the RDMnet message of a packet with the start code 0xCC. An error is
+// returned if the packet has another start code.
+func DecodeRDMnet(dp DataPacket) ([]byte, error) {
+	if dp.DmxStartCode() != RDMnetStartCode {
+		return nil, fmt.Errorf("the start code was %#x and therefore is not the RDMnet start code %#x", dp.DmxStartCode(), RDMnetStartCode)
+	}
+	return append([]byte(nil), dp.Data()...), nil
+}
@@ -0,0 +1,41 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRDMnet(t *testing.T) {
+	payload := []byte{0xF0, 0x01, 0x02, 0x03, 0x04} //odd length, so no padding has to happen
+	p, err := EncodeRDMnet(7, [16]byte{1}, "rdmnet", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := NewDataPacketRaw(p.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.DmxStartCode() != RDMnetStartCode || raw.Universe() != 7 || raw.SourceName() != "rdmnet" {
+		t.Errorf("Wrong packet! Start code: %#x; Universe: %v; Source name: %v", raw.DmxStartCode(), raw.Universe(), raw.SourceName())
+	}
+	if raw.PreviewData() || raw.StreamTerminated() {
+		t.Errorf("Wrong flags! Preview data: %v; Stream terminated: %v", raw.PreviewData(), raw.StreamTerminated())
+	}
+	decoded, err := DecodeRDMnet(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, payload) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", decoded, payload)
+	}
+
+	if _, err := DecodeRDMnet(NewDataPacket()); err == nil {
+		t.Error("Err was nil! Should have been an error for a DMX packet!")
+	}
+	if _, err := EncodeRDMnet(0, [16]byte{1}, "rdmnet", payload); err == nil {
+		t.Error("Err was nil! Should have been an error for universe 0!")
+	}
+	if _, err := EncodeRDMnet(1, [16]byte{1}, "rdmnet", make([]byte, 513)); err == nil {
+		t.Error("Err was nil! Should have been an error for a too long payload!")
+	}
+}
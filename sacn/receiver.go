@@ -1,7 +1,11 @@
 package sacn
 
 import (
+	"fmt"
+	"io"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/ipv4"
@@ -10,12 +14,19 @@ import (
 // Set the timeout according to the E1.31 protocol
 const timeoutMs = 2500
 
+// the number of packets that are buffered in a listener channel, before packets get dropped
+const listenerBufferSize = 16
+
+// the default for the maximum number of sources that are tracked per universe
+const defaultMaxSources = 10
+
 // ReceiverSocket is used to listen on a network interface for sACN data.
 // The OnChangeCallback is used for changed DMX data. So if a source or priority changed,
 // this callback will not be invoked if not the DMX data has changed.
 // This Receiver checks for out-of-order packets and sorts out packets with too low priority.
 type ReceiverSocket struct {
 	socket             *ipv4.PacketConn
+	groups             multicastGroups // used for joining and leaving multicast groups. Normally the socket
 	stopListener       chan struct{}
 	multicastInterface *net.Interface // the interface that is used for joining multicast groups
 	//OnChangeCallback gets called if the data on one universe has changed. Gets called in own goroutine
@@ -24,6 +35,96 @@ type ReceiverSocket struct {
 	timeoutCallback func(universe uint16)
 	lastDatas       map[uint16]lastData
 	timeoutCalled   map[uint16]bool //true, if the timeout was called. To prevent send a timeout callback twice
+	//OnSourceAdded gets called if a new source appeared on a universe. Gets called in own goroutine
+	onSourceAdded      func(universe uint16, cid [16]byte, sourceName string)
+	mu                 sync.Mutex //protects the listener maps and the sources
+	packetListeners    map[uint16][]chan DataPacket
+	dmxListeners       map[uint16][]chan []byte
+	discoveryListeners []chan DiscoveryPacket
+	allListeners       []chan UniverseFrame
+	sources            map[uint16]map[[16]byte]*sourceState //all sources that are currently sending on a universe
+	mergers            map[uint16]Merger                    //the mergers that are used for the DMX listeners
+	whitelists         map[uint16]map[[16]byte]bool         //the CIDs that are accepted per universe. No entry accepts all
+	nameFilters        map[uint16]string                    //the only source name that is accepted per universe
+	priorityThresholds map[uint16]byte                      //the minimum priority that is accepted per universe
+	ignoreTerminations map[uint16]bool                      //the universes whose terminated packets of unknown sources are discarded
+	blockedPackets     uint64                               //accessed atomically
+	rejectedSources    uint64                               //accessed atomically
+	lowPriority        uint64                               //accessed atomically
+	maxSources         int                                  //the maximum number of sources per universe. 0 for no limit
+	onSourceRejected   func(universe uint16, cid [16]byte)
+	latencyThreshold   time.Duration //the gap between two packets of a source that invokes onHighLatency
+	onHighLatency      func(universe uint16, src SourceInfo)
+	onTimestamp        func(universe uint16, delay time.Duration)
+	now                func() time.Time //the clock for the arrival time of packets
+	joined             map[uint16]bool  //the universes whose multicast-group was joined
+	autoResubscribe    bool             //if false, the packets of lost sources are ignored
+	lostSources        map[uint16]map[[16]byte]bool
+	sniffMu            sync.Mutex //protects the sniffers and is held while writing to them
+	sniffers           map[int]io.Writer
+	nextSniffer        int
+	forwarders         map[uint16][]*forwarder //the forwarders per received universe, see Forward
+	frames             map[uint16][]byte       //the last DMX data per universe, see GetUniverseData
+	rtp                bool                    //if true, the RTP header is removed from every packet
+	bufferDepths       map[uint16]int          //the buffer sizes of the listener channels per universe
+	tiebreaker         TiebreakerMode          //selects the source if multiple sources have the same priority
+}
+
+// sourceState holds the information about one source on one universe
+type sourceState struct {
+	sourceName      string
+	priority        byte
+	data            []byte //the data of the last packet that was in sequence
+	lastSeen        time.Time
+	lastSequence    byte
+	packetsReceived uint64
+	sequenceErrors  uint64
+	droppedFrames   uint64
+}
+
+// SourceInfo holds information about a source that is sending on a universe.
+type SourceInfo struct {
+	CID             [16]byte
+	SourceName      string
+	Priority        byte
+	LastSeen        time.Time //the time the last packet of the source was received
+	PacketsReceived uint64
+	SequenceErrors  uint64 //the number of packets that were out of order
+	DroppedFrames   uint64 //the number of frames that were dropped, because a listener channel was full
+}
+
+// LastPacketAge returns the time since the last packet of the source arrived.
+func (s SourceInfo) LastPacketAge() time.Duration {
+	return time.Since(s.LastSeen)
+}
+
+// ReceiverOption is used to configure a ReceiverSocket on creation via NewReceiverSocket.
+type ReceiverOption func(r *ReceiverSocket)
+
+// WithInterface sets the network interface that is used for joining multicast groups.
+// This overrides the interface that was given to NewReceiverSocket. On hosts with multiple network
+// interfaces this should be set, because otherwise the OS picks an interface.
+func WithInterface(ifi *net.Interface) ReceiverOption {
+	return func(r *ReceiverSocket) {
+		r.multicastInterface = ifi
+	}
+}
+
+// WithAutoResubscribe sets if a source that was lost, because it timed out or terminated its stream, is
+// accepted again when it resumes sending. The default is true: the state of the source is reset and the
+// OnSourceAdded callback is called again. If false, the packets of a lost source are ignored, until the
+// universe is joined again via JoinUniverse or left via LeaveUniverse. Ignored packets are counted in the
+// BlockedPackets of the Stats.
+func WithAutoResubscribe(enabled bool) ReceiverOption {
+	return func(r *ReceiverSocket) {
+		r.autoResubscribe = enabled
+	}
+}
+
+// multicastGroups is the part of the socket that is used for multicast group membership
+type multicastGroups interface {
+	JoinGroup(ifi *net.Interface, group net.Addr) error
+	LeaveGroup(ifi *net.Interface, group net.Addr) error
 }
 
 type lastData struct {
@@ -38,9 +139,10 @@ This bind is only used for unicast receiving.
 The net.Interface is used to join multicast groups. On some OS (eg Windows) you have
 to provide an interface for multicast to work. On others "nil" may be enough. If you don't want
 to use multicast for receiving, just provide "nil".
+Additional options like WithInterface can be provided.
 */
-func NewReceiverSocket(bind string, ifi *net.Interface) (*ReceiverSocket, error) {
-	r := &ReceiverSocket{}
+func NewReceiverSocket(bind string, ifi *net.Interface, opts ...ReceiverOption) (*ReceiverSocket, error) {
+	r := newReceiverSocket()
 
 	ServerConn, err := net.ListenPacket("udp4", bind+":5568")
 	if err != nil {
@@ -48,24 +150,73 @@ func NewReceiverSocket(bind string, ifi *net.Interface) (*ReceiverSocket, error)
 	}
 	r.multicastInterface = ifi
 	r.socket = ipv4.NewPacketConn(ServerConn)
-	r.lastDatas = make(map[uint16]lastData)
-	r.timeoutCalled = make(map[uint16]bool)
+	r.groups = r.socket
+	for _, opt := range opts {
+		opt(r)
+	}
 	return r, nil
 }
 
+// newReceiverSocket creates a receiver with all internal stores initialized, but without any socket
+func newReceiverSocket() *ReceiverSocket {
+	return &ReceiverSocket{
+		lastDatas:          make(map[uint16]lastData),
+		timeoutCalled:      make(map[uint16]bool),
+		packetListeners:    make(map[uint16][]chan DataPacket),
+		dmxListeners:       make(map[uint16][]chan []byte),
+		sources:            make(map[uint16]map[[16]byte]*sourceState),
+		mergers:            make(map[uint16]Merger),
+		whitelists:         make(map[uint16]map[[16]byte]bool),
+		nameFilters:        make(map[uint16]string),
+		priorityThresholds: make(map[uint16]byte),
+		ignoreTerminations: make(map[uint16]bool),
+		joined:             make(map[uint16]bool),
+		autoResubscribe:    true,
+		lostSources:        make(map[uint16]map[[16]byte]bool),
+		frames:             make(map[uint16][]byte),
+		bufferDepths:       make(map[uint16]int),
+		maxSources:         defaultMaxSources,
+		now:                time.Now,
+	}
+}
+
 // JoinUniverse joins the used udp socket to the multicast-group that is used for the universe.
 // After the multicast-group was joined, any source that transmit on this universe via multicast
 // should reach this socket.
 // Please read the notice above about multicast use.
 func (r *ReceiverSocket) JoinUniverse(universe uint16) {
-	r.socket.JoinGroup(r.multicastInterface, calcMulticastUDPAddr(universe))
+	r.mu.Lock()
+	delete(r.lostSources, universe)
+	r.mu.Unlock()
+	if r.groups.JoinGroup(r.multicastInterface, calcMulticastUDPAddr(universe)) == nil {
+		r.mu.Lock()
+		r.joined[universe] = true
+		r.mu.Unlock()
+	}
 }
 
-// LeaveUniverse will leave the multicast-group of the given universe.
-// If the the socket was not joined to the multicast-group nothing will happen.
+// LeaveUniverse will leave the multicast-group of the given universe, if the socket was joined to it.
+// All channels of ListenUniverse and ListenDMX for this universe are closed and the tracked sources
+// of the universe are removed. An error is returned if the multicast-group could not be left.
 // Please note, that if you leave a group, a timeout may occur, because no more data has arrived.
-func (r *ReceiverSocket) LeaveUniverse(universe uint16) {
-	r.socket.LeaveGroup(r.multicastInterface, calcMulticastUDPAddr(universe))
+func (r *ReceiverSocket) LeaveUniverse(universe uint16) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ch := range r.packetListeners[universe] {
+		close(ch)
+	}
+	delete(r.packetListeners, universe)
+	for _, ch := range r.dmxListeners[universe] {
+		close(ch)
+	}
+	delete(r.dmxListeners, universe)
+	delete(r.sources, universe)
+	delete(r.lostSources, universe)
+	if !r.joined[universe] {
+		return nil
+	}
+	delete(r.joined, universe)
+	return r.groups.LeaveGroup(r.multicastInterface, calcMulticastUDPAddr(universe))
 }
 
 // Close will close the open udp socket and stops the running goroutine.
@@ -90,8 +241,220 @@ func (r *ReceiverSocket) SetOnChangeCallback(callback func(old DataPacket, new D
 	r.onChangeCallback = callback
 }
 
+// SetOnSourceAdded sets the callback that gets called if a new source (identified by its CID) starts
+// sending on a universe. It is called once per universe and source. If a source timed out and appears
+// again, the callback is called again.
+func (r *ReceiverSocket) SetOnSourceAdded(callback func(universe uint16, cid [16]byte, sourceName string)) {
+	r.onSourceAdded = callback
+}
+
 // SetTimeoutCallback sets the callback for timeouts. The callback gets called every time a timeout is
-// recognized.
+// recognized. A source that terminates its stream (stream terminated flag) also causes a timeout.
 func (r *ReceiverSocket) SetTimeoutCallback(callback func(universe uint16)) {
 	r.timeoutCallback = callback
 }
+
+// ListenUniverse returns a channel on which every accepted packet of the given universe is delivered.
+// In contrast to the OnChangeCallback, all packets that passed the sequence and priority checks are
+// delivered, even if the DMX data has not changed. If the channel is not read fast enough, the oldest
+// packets are dropped, see SetBufferDepth. The channel gets closed when the receiver is closed.
+func (r *ReceiverSocket) ListenUniverse(universe uint16) (<-chan DataPacket, error) {
+	if err := checkListenUniverse(universe); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	ch := make(chan DataPacket, r.bufferDepth(universe))
+	r.packetListeners[universe] = append(r.packetListeners[universe], ch)
+	r.mu.Unlock()
+	return ch, nil
+}
+
+// ListenDMX works like ListenUniverse, but only delivers the DMX data of the packets.
+// If a Merger is set for the universe, the merged data of all sources is delivered instead.
+func (r *ReceiverSocket) ListenDMX(universe uint16) (<-chan []byte, error) {
+	if err := checkListenUniverse(universe); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	ch := make(chan []byte, r.bufferDepth(universe))
+	r.dmxListeners[universe] = append(r.dmxListeners[universe], ch)
+	r.mu.Unlock()
+	return ch, nil
+}
+
+// Subscribe joins all given universes and returns a DMX channel for every universe, like ListenDMX.
+// All universes are registered under a single lock, so no packets of one universe are handled before
+// all channels exist. All universes share the socket of the receiver. Universes that appear more than
+// once get only one channel. If some universes are invalid or could not be joined, the channels of the
+// other universes are still returned together with an error that lists the failed universes.
+func (r *ReceiverSocket) Subscribe(universes []uint16) (map[uint16]<-chan []byte, error) {
+	chs := make(map[uint16]<-chan []byte, len(universes))
+	failed := make([]string, 0)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, universe := range universes {
+		if _, ok := chs[universe]; ok {
+			continue
+		}
+		if err := checkListenUniverse(universe); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		if r.groups != nil && !r.joined[universe] {
+			if err := r.groups.JoinGroup(r.multicastInterface, calcMulticastUDPAddr(universe)); err != nil {
+				failed = append(failed, fmt.Sprintf("could not join universe %v: %v", universe, err))
+				continue
+			}
+			r.joined[universe] = true
+		}
+		delete(r.lostSources, universe)
+		ch := make(chan []byte, r.bufferDepth(universe))
+		r.dmxListeners[universe] = append(r.dmxListeners[universe], ch)
+		chs[universe] = ch
+	}
+	if len(failed) > 0 {
+		return chs, fmt.Errorf("could not subscribe to %v universes: %v", len(failed), strings.Join(failed, "; "))
+	}
+	return chs, nil
+}
+
+// SetBufferDepth sets the buffer size of the channels that are returned by ListenUniverse and ListenDMX
+// for the universe. The default is 16 frames. If a channel is full, the oldest frame is dropped when a
+// new one arrives, so a slow consumer never blocks the receiver. Dropped frames are counted in the
+// DroppedFrames of the source (see GetActiveSources), merged frames are not counted.
+// The depth has to be set before the universe is joined and applies to the channels that are created
+// afterwards. Otherwise an error is returned.
+func (r *ReceiverSocket) SetBufferDepth(universe uint16, depth int) error {
+	if depth < 1 {
+		return fmt.Errorf("the buffer depth was %v and has to be at least 1", depth)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.joined[universe] {
+		return fmt.Errorf("the universe %v is already joined", universe)
+	}
+	r.bufferDepths[universe] = depth
+	return nil
+}
+
+// bufferDepth returns the buffer size of the listener channels of the universe. The caller has to hold
+// the lock.
+func (r *ReceiverSocket) bufferDepth(universe uint16) int {
+	if depth, ok := r.bufferDepths[universe]; ok {
+		return depth
+	}
+	return listenerBufferSize
+}
+
+// UniverseFrame is a frame that was received on any universe. See SubscribeAll.
+type UniverseFrame struct {
+	Universe   uint16
+	Data       []byte
+	Source     SourceInfo //the source that sent the frame
+	ReceivedAt time.Time
+}
+
+// SubscribeAll returns a channel on which the frames of all packets of all universes are delivered,
+// without registering the universes before. This is useful for monitoring tools. In contrast to
+// ListenUniverse, no priority or sequence checks are done, so every packet of every source is delivered.
+// Only the filters like SetCIDWhitelist are applied. Unicast packets are received on all universes,
+// for multicast the universes still have to be joined via JoinUniverse.
+// If the channel is not read fast enough, frames are dropped. The channel gets closed when the
+// receiver is closed.
+func (r *ReceiverSocket) SubscribeAll() (<-chan UniverseFrame, error) {
+	ch := make(chan UniverseFrame, listenerBufferSize)
+	r.mu.Lock()
+	r.allListeners = append(r.allListeners, ch)
+	r.mu.Unlock()
+	return ch, nil
+}
+
+func checkListenUniverse(universe uint16) error {
+	if !IsValidDataUniverse(universe) {
+		return fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", universe)
+	}
+	return nil
+}
+
+// ListenDiscovery returns a channel on which all received universe discovery packets are delivered.
+// The socket joins the multicast group of the discovery universe 64214, so sources that announce
+// their universes via multicast can be found. If the channel is not read fast enough, packets are
+// dropped. The channel gets closed when the receiver is closed.
+func (r *ReceiverSocket) ListenDiscovery() (<-chan DiscoveryPacket, error) {
+	if r.groups != nil {
+		if err := r.groups.JoinGroup(r.multicastInterface, calcMulticastUDPAddr(discoveryUniverse)); err != nil {
+			return nil, err
+		}
+	}
+	ch := make(chan DiscoveryPacket, listenerBufferSize)
+	r.mu.Lock()
+	r.discoveryListeners = append(r.discoveryListeners, ch)
+	r.mu.Unlock()
+	return ch, nil
+}
+
+// GetActiveSources returns information about all sources that are currently sending on the given universe.
+// Sources that have timed out are not included.
+func (r *ReceiverSocket) GetActiveSources(universe uint16) []SourceInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	infos := make([]SourceInfo, 0, len(r.sources[universe]))
+	for cid, source := range r.sources[universe] {
+		if time.Since(source.lastSeen) > time.Millisecond*timeoutMs {
+			continue
+		}
+		infos = append(infos, source.info(cid))
+	}
+	return infos
+}
+
+// GetUniverseData returns the DMX data that was last received on the universe, as it would be delivered
+// by ListenDMX. If a Merger is set for the universe, the merged data of all sources is returned.
+// This is meant for polling the current state instead of reading a channel. The returned slice is a copy.
+// An error is returned if no data was received on the universe yet.
+func (r *ReceiverSocket) GetUniverseData(universe uint16) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, ok := r.frames[universe]
+	if !ok {
+		return nil, fmt.Errorf("no data was received on universe %v", universe)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// info returns the public information about the source
+func (s *sourceState) info(cid [16]byte) SourceInfo {
+	return SourceInfo{
+		CID:             cid,
+		SourceName:      s.sourceName,
+		Priority:        s.priority,
+		LastSeen:        s.lastSeen,
+		PacketsReceived: s.packetsReceived,
+		SequenceErrors:  s.sequenceErrors,
+		DroppedFrames:   s.droppedFrames,
+	}
+}
+
+// SetOnHighLatency sets a callback that gets called, if the gap between two packets of a source exceeds
+// the threshold. This helps to diagnose jitter in the network. The source info contains the arrival time
+// of the delayed packet. Gets called in own goroutine. Use nil to remove the callback.
+func (r *ReceiverSocket) SetOnHighLatency(threshold time.Duration, fn func(universe uint16, src SourceInfo)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencyThreshold = threshold
+	r.onHighLatency = fn
+}
+
+// SetMerger sets the Merger for the given universe. If a merger is set, the channels of ListenDMX do not
+// get the data of the source that won the priority arbitration, but the merged data of all sources that
+// are sending on the universe. Every packet of any source causes a new merged frame.
+// Use nil to remove the merger.
+func (r *ReceiverSocket) SetMerger(universe uint16, m Merger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m == nil {
+		delete(r.mergers, universe)
+		return
+	}
+	r.mergers[universe] = m
+}
@@ -0,0 +1,146 @@
+package sacn
+
+import (
+	"sync/atomic"
+)
+
+// ReceiverStats holds counters of a ReceiverSocket.
+type ReceiverStats struct {
+	BlockedPackets  uint64 //the number of packets that were discarded by a filter
+	RejectedSources uint64 //the number of packets of new sources that were discarded, because of SetMaxSourcesPerUniverse
+	LowPriority     uint64 //the number of packets that were discarded, because of SetPriorityThreshold
+}
+
+// SetCIDWhitelist sets the CIDs of the sources that are accepted on the given universe. Packets of all
+// other sources are discarded silently, before any other processing takes place. Discarded packets are
+// counted in the BlockedPackets of the Stats. An empty list accepts all sources, which is the default.
+func (r *ReceiverSocket) SetCIDWhitelist(universe uint16, cids [][16]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(cids) == 0 {
+		delete(r.whitelists, universe)
+		return
+	}
+	whitelist := make(map[[16]byte]bool, len(cids))
+	for _, cid := range cids {
+		whitelist[cid] = true
+	}
+	r.whitelists[universe] = whitelist
+}
+
+// ClearCIDWhitelist removes the whitelist of the given universe, so all sources are accepted again.
+func (r *ReceiverSocket) ClearCIDWhitelist(universe uint16) {
+	r.SetCIDWhitelist(universe, nil)
+}
+
+// SetSourceNameFilter makes the receiver discard all packets on the given universe, whose source name
+// is not exactly the given name (case-sensitive). Discarded packets are counted in the BlockedPackets of
+// the Stats. Note: this filter is only advisory, because the source name is not unique and can be chosen
+// freely by every source. For security, SetCIDWhitelist should be preferred.
+func (r *ReceiverSocket) SetSourceNameFilter(universe uint16, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nameFilters[universe] = name
+}
+
+// ClearSourceNameFilter removes the source name filter of the given universe, so all source names
+// are accepted again.
+func (r *ReceiverSocket) ClearSourceNameFilter(universe uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.nameFilters, universe)
+}
+
+// SetPriorityThreshold makes the receiver discard all packets on the given universe with a priority below
+// minPrio. This is a hard floor in addition to the priority arbitration of E1.31 6.2.3, so a background
+// source is ignored even if no other source is sending. Discarded packets are counted in the LowPriority
+// of the Stats. A threshold of 0 accepts all priorities, which is the default.
+func (r *ReceiverSocket) SetPriorityThreshold(universe uint16, minPrio byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if minPrio == 0 {
+		delete(r.priorityThresholds, universe)
+		return
+	}
+	r.priorityThresholds[universe] = minPrio
+}
+
+// SetIgnoreUnknownTerminations sets if packets with the stream terminated flag are discarded on the given
+// universe, if their source is not tracked on the universe, e.g. because it never sent data. This protects
+// against misconfigured devices that send such packets with random CIDs. Otherwise the source is handled
+// as lost, see WithAutoResubscribe. Discarded packets are counted in the BlockedPackets of the Stats.
+// The default is false, which is compliant to E1.31.
+func (r *ReceiverSocket) SetIgnoreUnknownTerminations(universe uint16, ignore bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !ignore {
+		delete(r.ignoreTerminations, universe)
+		return
+	}
+	r.ignoreTerminations[universe] = true
+}
+
+// SetMaxSourcesPerUniverse sets the maximum number of sources that are tracked per universe. This protects
+// against devices that flood a universe with packets of many different CIDs. If the limit is reached,
+// packets of new sources are discarded until a tracked source timed out or terminated its stream.
+// Discarded packets are counted in the RejectedSources of the Stats and the OnSourceRejected callback
+// is called. The default is 10. A value of 0 or less disables the limit.
+func (r *ReceiverSocket) SetMaxSourcesPerUniverse(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxSources = n
+}
+
+// SetOnSourceRejected sets the callback that gets called for every packet of a new source, that was
+// discarded because of the limit of SetMaxSourcesPerUniverse. Gets called in its own goroutine.
+func (r *ReceiverSocket) SetOnSourceRejected(callback func(universe uint16, cid [16]byte)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onSourceRejected = callback
+}
+
+// Stats returns the counters of the receiver.
+func (r *ReceiverSocket) Stats() ReceiverStats {
+	return ReceiverStats{
+		BlockedPackets:  atomic.LoadUint64(&r.blockedPackets),
+		RejectedSources: atomic.LoadUint64(&r.rejectedSources),
+		LowPriority:     atomic.LoadUint64(&r.lowPriority),
+	}
+}
+
+// accept checks the packet against all filters of its universe. Returns false if the packet has to be
+// discarded and counts it as blocked.
+func (r *ReceiverSocket) accept(p DataPacket) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if whitelist, ok := r.whitelists[p.Universe()]; ok && !whitelist[p.CID()] {
+		atomic.AddUint64(&r.blockedPackets, 1)
+		return false
+	}
+	if name, ok := r.nameFilters[p.Universe()]; ok && p.SourceName() != name {
+		atomic.AddUint64(&r.blockedPackets, 1)
+		return false
+	}
+	if p.Priority() < r.priorityThresholds[p.Universe()] {
+		atomic.AddUint64(&r.lowPriority, 1)
+		return false
+	}
+	if r.lostSources[p.Universe()][p.CID()] {
+		atomic.AddUint64(&r.blockedPackets, 1)
+		return false
+	}
+	sources := r.sources[p.Universe()]
+	_, known := sources[p.CID()]
+	if !known && p.StreamTerminated() && r.ignoreTerminations[p.Universe()] {
+		atomic.AddUint64(&r.blockedPackets, 1)
+		return false
+	}
+	if !known && r.maxSources > 0 && len(sources) >= r.maxSources {
+		atomic.AddUint64(&r.rejectedSources, 1)
+		if r.onSourceRejected != nil {
+			go r.onSourceRejected(p.Universe(), p.CID())
+		}
+		return false
+	}
+	return true
+}
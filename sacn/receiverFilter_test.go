@@ -0,0 +1,156 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSetCIDWhitelist(t *testing.T) {
+	r := newReceiverSocket()
+	r.SetCIDWhitelist(1, [][16]byte{{1}})
+	dmx, _ := r.ListenDMX(1)
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1}))
+	r.handle(newTestPacket(t, 1, [16]byte{2}, 200, []byte{2}))
+
+	if out := <-dmx; !bytes.Equal(out, []byte{1, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{1, 0})
+	}
+	select {
+	case out := <-dmx:
+		t.Errorf("Data of a source that is not whitelisted was delivered: %v", out)
+	default:
+	}
+	if stats := r.Stats(); stats.BlockedPackets != 1 {
+		t.Errorf("Wrong blocked packets! Was: %v; Should've been: %v", stats.BlockedPackets, 1)
+	}
+
+	r.ClearCIDWhitelist(1)
+	r.handle(newTestPacket(t, 1, [16]byte{2}, 200, []byte{2}))
+	if out := <-dmx; !bytes.Equal(out, []byte{2, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{2, 0})
+	}
+}
+
+func TestSetSourceNameFilter(t *testing.T) {
+	r := newReceiverSocket()
+	r.SetSourceNameFilter(1, "Console")
+	dmx, _ := r.ListenDMX(1)
+	other := newTestPacket(t, 1, [16]byte{2}, 200, []byte{2})
+	other.SetSourceName("console")
+	r.handle(other)
+	console := newTestPacket(t, 1, [16]byte{1}, 100, []byte{1})
+	console.SetSourceName("Console")
+	r.handle(console)
+
+	if out := <-dmx; !bytes.Equal(out, []byte{1, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{1, 0})
+	}
+	if stats := r.Stats(); stats.BlockedPackets != 1 {
+		t.Errorf("Wrong blocked packets! Was: %v; Should've been: %v", stats.BlockedPackets, 1)
+	}
+
+	r.ClearSourceNameFilter(1)
+	other.SequenceIncr()
+	r.handle(other)
+	if out := <-dmx; !bytes.Equal(out, []byte{2, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{2, 0})
+	}
+}
+
+func TestSetPriorityThreshold(t *testing.T) {
+	r := newReceiverSocket()
+	r.SetPriorityThreshold(1, 100)
+	dmx, _ := r.ListenDMX(1)
+	r.handle(newTestPacket(t, 1, [16]byte{2}, 50, []byte{2}))
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1}))
+
+	if out := <-dmx; !bytes.Equal(out, []byte{1, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{1, 0})
+	}
+	select {
+	case out := <-dmx:
+		t.Errorf("Data of a source below the threshold was delivered: %v", out)
+	default:
+	}
+	if stats := r.Stats(); stats.LowPriority != 1 || stats.BlockedPackets != 0 {
+		t.Errorf("Wrong stats! Was: %+v; Should've been: %v low priority packets", stats, 1)
+	}
+	//other universes are not affected
+	other, _ := r.ListenDMX(2)
+	r.handle(newTestPacket(t, 2, [16]byte{2}, 50, []byte{2}))
+	if out := <-other; !bytes.Equal(out, []byte{2, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{2, 0})
+	}
+}
+
+func TestSetMaxSourcesPerUniverse(t *testing.T) {
+	r := newReceiverSocket()
+	r.SetMaxSourcesPerUniverse(3)
+	rejected := make(chan [16]byte, 1)
+	r.SetOnSourceRejected(func(universe uint16, cid [16]byte) {
+		rejected <- cid
+	})
+	for i := byte(1); i <= 4; i++ {
+		r.handle(newTestPacket(t, 1, [16]byte{i}, 100, []byte{i}))
+	}
+	if sources := r.GetActiveSources(1); len(sources) != 3 {
+		t.Errorf("Wrong number of sources! Was: %v; Should've been: %v", len(sources), 3)
+	}
+	if stats := r.Stats(); stats.RejectedSources != 1 {
+		t.Errorf("Wrong rejected sources! Was: %v; Should've been: %v", stats.RejectedSources, 1)
+	}
+	select {
+	case cid := <-rejected:
+		if cid != [16]byte{4} {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", cid, [16]byte{4})
+		}
+	case <-time.After(time.Second):
+		t.Error("The callback was not called!")
+	}
+	//known sources are still accepted
+	p := newTestPacket(t, 1, [16]byte{1}, 100, []byte{1})
+	p.SequenceIncr()
+	r.handle(p)
+	if stats := r.Stats(); stats.RejectedSources != 1 {
+		t.Errorf("Wrong rejected sources! Was: %v; Should've been: %v", stats.RejectedSources, 1)
+	}
+}
+
+func TestSetIgnoreUnknownTerminations(t *testing.T) {
+	r := newReceiverSocket()
+	WithAutoResubscribe(false)(r)
+	timeouts := make(chan uint16, 10)
+	r.SetTimeoutCallback(func(universe uint16) {
+		timeouts <- universe
+	})
+	r.SetIgnoreUnknownTerminations(1, true)
+	for _, univ := range []uint16{1, 2} {
+		r.handle(newTestPacket(t, univ, [16]byte{1}, 100, []byte{1}))
+		//the source is no longer tracked, like after its timeout, but its data is the last of the universe
+		r.mu.Lock()
+		delete(r.sources, univ)
+		r.mu.Unlock()
+		p := newTestPacket(t, univ, [16]byte{1}, 100, []byte{1})
+		p.SetSequence(1)
+		p.SetStreamTerminated(true)
+		r.handle(p)
+	}
+
+	if univ := <-timeouts; univ != 2 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", univ, 2)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(timeouts) != 0 {
+		t.Errorf("The termination of an unknown source caused a timeout on universe %v!", <-timeouts)
+	}
+	if stats := r.Stats(); stats.BlockedPackets != 1 {
+		t.Errorf("Wrong blocked packets! Was: %v; Should've been: %v", stats.BlockedPackets, 1)
+	}
+	//only the source on universe 2 was lost, so its packets are ignored
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lostSources[1][[16]byte{1}] || !r.lostSources[2][[16]byte{1}] {
+		t.Errorf("Wrong lost sources! Was: %v", r.lostSources)
+	}
+}
@@ -9,7 +9,7 @@ import (
 //It dispatches the received packets to the corresponding handlers.
 func (r *ReceiverSocket) startListener() {
 	go func() {
-		buf := make([]byte, 638)
+		buf := make([]byte, discoveryHeaderLength+2*discoveryMaxUniverses) //big enough for every sACN packet
 	Loop:
 		for {
 			select {
@@ -24,21 +24,59 @@ func (r *ReceiverSocket) startListener() {
 				//that means we did not receive a packet in 2,5s at all
 				r.checkForTimeouts()
 			}
-			p, err := NewDataPacketRaw(buf[0:n])
-			if err != nil {
-				continue //if the packet could not be parsed, just skip it
-			}
-			//send the packet to the responding handler and the other are getting nil
-			r.handle(p)
+			r.handleRaw(buf[0:n])
 		}
-		r.socket.Close()     //close the channel, if the listener is finished
+		r.socket.Close() //close the channel, if the listener is finished
+		r.closeListeners()
 		r.stopListener = nil //set the channel to nil, so it can be used as indicator if the routine is running
 	}()
 }
 
+//handleRaw parses the raw bytes and passes the packet to the corresponding handler
+func (r *ReceiverSocket) handleRaw(raw []byte) {
+	r.sniff(raw)
+	if r.rtp {
+		var ok bool
+		if raw, ok = stripRTPHeader(raw); !ok {
+			return //no RTP packet
+		}
+	}
+	if isDiscoveryPacket(raw) {
+		d, err := ParseDiscoveryPacket(raw)
+		if err == nil {
+			r.dispatchDiscovery(d)
+		}
+		return
+	}
+	if len(raw) < 22 || getAsUint32(raw[18:22]) != vectorRootE131Data {
+		return //unknown packet, just skip it
+	}
+	p, err := NewDataPacketRaw(raw)
+	if err != nil {
+		return //if the packet could not be parsed, just skip it
+	}
+	r.handle(p)
+}
+
 //the handler is responsible for checking all necessary things to decide if callbacks should be invoked
 func (r *ReceiverSocket) handle(p DataPacket) {
 	r.checkForTimeouts()
+	if !r.accept(p) {
+		return
+	}
+	if p.DmxStartCode() == TimestampStartCode {
+		r.handleTimestamp(p)
+		return
+	}
+	if p.StreamTerminated() {
+		r.terminateSource(p)
+		return
+	}
+	r.trackSource(p)
+	r.dispatchAll(p)
+	if merged := r.merge(p.Universe()); merged != nil {
+		r.forwardMerged(p.Universe(), merged)
+	}
 	//check if we had a change in priority to the last data we received on the universe
 	last, ok := r.lastDatas[p.Universe()]
 	if ok {
@@ -54,8 +92,12 @@ func (r *ReceiverSocket) handle(p DataPacket) {
 		//we have last data for this universe, so check the priority
 		if last.lastPacket.Priority() == p.Priority() {
 			//we have the same priority
-			//check sequence:
-			if checkSequ(last.lastPacket.Sequence(), p.Sequence()) {
+			sameSource := last.lastPacket.CID() == p.CID()
+			if !sameSource && !r.winsTie(last.lastPacket, p) {
+				return //the other source stays selected
+			}
+			//check sequence, if it is the same source:
+			if !sameSource || checkSequ(last.lastPacket.Sequence(), p.Sequence()) {
 				//sequence is good:; check if the data has changed. If so, then invoke callback
 				if !bytes.Equal(last.lastPacket.Data(), p.Data()) {
 					r.invokeCallback(p)
@@ -91,17 +133,251 @@ func (r *ReceiverSocket) invokeCallback(new DataPacket) {
 	}
 }
 
-//storeLastPacket stores the packet in the lastDatas store
+//storeLastPacket stores the packet in the lastDatas store and delivers it to the listeners
 func (r *ReceiverSocket) storeLastPacket(p DataPacket) {
 	r.lastDatas[p.Universe()] = lastData{
 		lastPacket: p.copy(),
 		lastTime:   time.Now(),
 	}
 	r.timeoutCalled[p.Universe()] = false
+	r.dispatch(p)
+	r.forward(p)
+}
+
+//dispatch delivers the packet to all listeners of its universe. If a listener is full, its oldest frame
+//is dropped and counted for the source of the packet.
+func (r *ReceiverSocket) dispatch(p DataPacket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dropped := uint64(0)
+	for _, ch := range r.packetListeners[p.Universe()] {
+		if !sendDropOldest(ch, p.copy()) {
+			dropped++
+		}
+	}
+	if _, ok := r.mergers[p.Universe()]; !ok { //otherwise the DMX listeners get the merged data
+		r.frames[p.Universe()] = append([]byte(nil), p.Data()...)
+		for _, ch := range r.dmxListeners[p.Universe()] {
+			if !sendDMXDropOldest(ch, append([]byte(nil), p.Data()...)) {
+				dropped++
+			}
+		}
+	}
+	if source, ok := r.sources[p.Universe()][p.CID()]; ok {
+		source.droppedFrames += dropped
+	}
+}
+
+//sendDropOldest sends the packet to the channel. If the channel is full, the oldest packet is dropped.
+//Returns false if a packet was dropped.
+func sendDropOldest(ch chan DataPacket, p DataPacket) bool {
+	dropped := false
+	for {
+		select {
+		case ch <- p:
+			return !dropped
+		default:
+		}
+		select {
+		case <-ch:
+			dropped = true
+		default: //the consumer read a packet in the meantime
+		}
+	}
+}
+
+//sendDMXDropOldest works like sendDropOldest for DMX data
+func sendDMXDropOldest(ch chan []byte, data []byte) bool {
+	dropped := false
+	for {
+		select {
+		case ch <- data:
+			return !dropped
+		default:
+		}
+		select {
+		case <-ch:
+			dropped = true
+		default: //the consumer read a frame in the meantime
+		}
+	}
+}
+
+//merge merges the data of all sources of the universe and delivers it to the DMX listeners,
+//if a merger is set for the universe. Returns the merged data or nil, if no merger is set
+func (r *ReceiverSocket) merge(universe uint16) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	merger, ok := r.mergers[universe]
+	if !ok || len(r.sources[universe]) == 0 {
+		return nil
+	}
+	frames := make([]SourceFrame, 0, len(r.sources[universe]))
+	for cid, source := range r.sources[universe] {
+		frames = append(frames, SourceFrame{
+			CID:         cid,
+			Priority:    source.priority,
+			Data:        source.data,
+			ArrivalTime: source.lastSeen,
+		})
+	}
+	merged := merger.Merge(frames)
+	r.frames[universe] = append([]byte(nil), merged...)
+	for _, ch := range r.dmxListeners[universe] {
+		sendDMXDropOldest(ch, append([]byte(nil), merged...))
+	}
+	return merged
+}
+
+//dispatchAll delivers the frame of the packet to all listeners of SubscribeAll
+func (r *ReceiverSocket) dispatchAll(p DataPacket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.allListeners) == 0 {
+		return
+	}
+	frame := UniverseFrame{
+		Universe:   p.Universe(),
+		Source:     r.sources[p.Universe()][p.CID()].info(p.CID()),
+		ReceivedAt: time.Now(),
+	}
+	for _, ch := range r.allListeners {
+		frame.Data = append([]byte(nil), p.Data()...)
+		select {
+		case ch <- frame:
+		default:
+		}
+	}
+}
+
+//dispatchDiscovery delivers the discovery packet to all discovery listeners that are ready
+func (r *ReceiverSocket) dispatchDiscovery(d DiscoveryPacket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ch := range r.discoveryListeners {
+		select {
+		case ch <- d:
+		default:
+		}
+	}
+}
+
+//closeListeners closes all listener channels and removes them
+func (r *ReceiverSocket) closeListeners() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for univ, chs := range r.packetListeners {
+		for _, ch := range chs {
+			close(ch)
+		}
+		delete(r.packetListeners, univ)
+	}
+	for univ, chs := range r.dmxListeners {
+		for _, ch := range chs {
+			close(ch)
+		}
+		delete(r.dmxListeners, univ)
+	}
+	for _, ch := range r.discoveryListeners {
+		close(ch)
+	}
+	r.discoveryListeners = nil
+	for _, ch := range r.allListeners {
+		close(ch)
+	}
+	r.allListeners = nil
+}
+
+//trackSource updates the state of the source of the packet and invokes the callback for new sources
+func (r *ReceiverSocket) trackSource(p DataPacket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sources, ok := r.sources[p.Universe()]
+	if !ok {
+		sources = make(map[[16]byte]*sourceState)
+		r.sources[p.Universe()] = sources
+	}
+	source, ok := sources[p.CID()]
+	if !ok {
+		source = &sourceState{}
+		sources[p.CID()] = source
+		if r.onSourceAdded != nil {
+			go r.onSourceAdded(p.Universe(), p.CID(), p.SourceName())
+		}
+		source.data = append([]byte(nil), p.Data()...)
+	} else if !checkSequ(source.lastSequence, p.Sequence()) {
+		source.sequenceErrors++
+	} else {
+		source.data = append([]byte(nil), p.Data()...)
+	}
+	now := r.now()
+	gap := now.Sub(source.lastSeen)
+	source.sourceName = p.SourceName()
+	source.priority = p.Priority()
+	source.lastSeen = now
+	source.lastSequence = p.Sequence()
+	source.packetsReceived++
+	if ok && r.onHighLatency != nil && gap > r.latencyThreshold {
+		go r.onHighLatency(p.Universe(), source.info(p.CID()))
+	}
+}
+
+//terminateSource handles a packet with the stream terminated flag. The source is removed immediately
+//and if it was the source of the last data of the universe, the timeoutCallback is called.
+func (r *ReceiverSocket) terminateSource(p DataPacket) {
+	r.mu.Lock()
+	if sources, ok := r.sources[p.Universe()]; ok {
+		delete(sources, p.CID())
+		if len(sources) == 0 {
+			delete(r.sources, p.Universe())
+		}
+	}
+	r.markLost(p.Universe(), p.CID())
+	r.mu.Unlock()
+	last, ok := r.lastDatas[p.Universe()]
+	if !ok || last.lastPacket.CID() != p.CID() {
+		return
+	}
+	delete(r.lastDatas, p.Universe())
+	delete(r.timeoutCalled, p.Universe())
+	r.stopForwarding(p.Universe())
+	if r.timeoutCallback != nil {
+		go r.timeoutCallback(p.Universe())
+	}
+}
+
+//removeTimedOutSources removes all sources that did not send a packet within the timeout
+func (r *ReceiverSocket) removeTimedOutSources() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for univ, sources := range r.sources {
+		for cid, source := range sources {
+			if time.Since(source.lastSeen) > time.Millisecond*timeoutMs {
+				delete(sources, cid)
+				r.markLost(univ, cid)
+			}
+		}
+		if len(sources) == 0 {
+			delete(r.sources, univ)
+		}
+	}
+}
+
+//markLost remembers the lost source, so its packets are ignored if auto resubscribing is disabled.
+//The caller has to hold the lock.
+func (r *ReceiverSocket) markLost(universe uint16, cid [16]byte) {
+	if r.autoResubscribe {
+		return
+	}
+	if _, ok := r.lostSources[universe]; !ok {
+		r.lostSources[universe] = make(map[[16]byte]bool)
+	}
+	r.lostSources[universe][cid] = true
 }
 
 //checkForTimeouts checks all last data if a universe had a timeout. Calls the timeoutCallback.
 func (r *ReceiverSocket) checkForTimeouts() {
+	r.removeTimedOutSources()
 	for univ, last := range r.lastDatas {
 		if time.Since(last.lastTime) > time.Millisecond*timeoutMs {
 			//timeout
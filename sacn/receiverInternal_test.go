@@ -0,0 +1,459 @@
+package sacn
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestPacket creates a packet as it would arrive on the wire
+func newTestPacket(t *testing.T, universe uint16, cid [16]byte, prio byte, data []byte) DataPacket {
+	p := NewDataPacket()
+	p.SetUniverse(universe)
+	p.SetCID(cid)
+	p.SetPriority(prio)
+	p.SetData(data)
+	raw, err := NewDataPacketRaw(p.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestListenUniverse(t *testing.T) {
+	r := newReceiverSocket()
+	packets, err := r.ListenUniverse(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dmx, err := r.ListenDMX(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ListenUniverse(0); err == nil {
+		t.Error("Err was nil! Should have been an error for universe 0!")
+	}
+
+	cid := [16]byte{1, 2, 3}
+	r.handle(newTestPacket(t, 1, cid, 120, []byte{1, 2, 3, 4}))
+	r.handle(newTestPacket(t, 2, cid, 120, []byte{5, 6}))
+
+	p := <-packets
+	if p.CID() != cid || p.Priority() != 120 || p.Universe() != 1 {
+		t.Errorf("Wrong output! Was: CID %v, priority %v, universe %v", p.CID(), p.Priority(), p.Universe())
+	}
+	if d := <-dmx; !bytes.Equal(d, []byte{1, 2, 3, 4}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", d, []byte{1, 2, 3, 4})
+	}
+	if len(packets) != 0 || len(dmx) != 0 {
+		t.Error("Packets of another universe were delivered!")
+	}
+
+	r.closeListeners()
+	if _, ok := <-packets; ok {
+		t.Error("Channel should have been closed!")
+	}
+}
+
+// mockGroups records the calls for joining and leaving multicast groups
+type mockGroups struct {
+	joined []string
+	left   []string
+	ifis   []*net.Interface
+}
+
+func (m *mockGroups) JoinGroup(ifi *net.Interface, group net.Addr) error {
+	m.joined = append(m.joined, group.String())
+	m.ifis = append(m.ifis, ifi)
+	return nil
+}
+
+func (m *mockGroups) LeaveGroup(ifi *net.Interface, group net.Addr) error {
+	m.left = append(m.left, group.String())
+	m.ifis = append(m.ifis, ifi)
+	return nil
+}
+
+func TestWithInterface(t *testing.T) {
+	ifi := &net.Interface{Index: 7, Name: "sacn0"}
+	r := newReceiverSocket()
+	WithInterface(ifi)(r)
+	groups := &mockGroups{}
+	r.groups = groups
+
+	r.JoinUniverse(1)
+	if len(groups.joined) != 1 || groups.joined[0] != "239.255.0.1:5568" {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", groups.joined, "239.255.0.1:5568")
+	}
+	if groups.ifis[0] != ifi {
+		t.Errorf("Wrong interface! Was: %v; Should've been: %v", groups.ifis[0], ifi)
+	}
+}
+
+func TestSequenceWraparound(t *testing.T) {
+	r := newReceiverSocket()
+	packets, _ := r.ListenUniverse(1)
+	p := newTestPacket(t, 1, [16]byte{1}, 100, []byte{1})
+	p.SetSequence(255)
+	r.handle(p)
+	p = newTestPacket(t, 1, [16]byte{1}, 100, []byte{2})
+	p.SetSequence(0)
+	r.handle(p)
+
+	<-packets
+	select {
+	case p := <-packets:
+		if p.Sequence() != 0 {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Sequence(), 0)
+		}
+	default:
+		t.Error("The packet with sequence 0 after 255 was not accepted!")
+	}
+}
+
+func TestOnSourceAdded(t *testing.T) {
+	r := newReceiverSocket()
+	added := make(chan [16]byte, 10)
+	r.SetOnSourceAdded(func(universe uint16, cid [16]byte, sourceName string) {
+		added <- cid
+	})
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1}))
+	r.handle(newTestPacket(t, 1, [16]byte{2}, 100, []byte{2}))
+	for i := 0; i < 3; i++ {
+		r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{3}))
+	}
+
+	first, second := <-added, <-added
+	if first == second {
+		t.Errorf("The callback was called twice for the same CID %v", first)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(added) != 0 {
+		t.Errorf("The callback was called %v times too often", len(added))
+	}
+}
+
+func TestStreamTerminated(t *testing.T) {
+	r := newReceiverSocket()
+	timeouts := make(chan uint16, 10)
+	r.SetTimeoutCallback(func(universe uint16) {
+		timeouts <- universe
+	})
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1}))
+	//a terminated packet of an unknown source must not end the stream of the other source
+	p := newTestPacket(t, 1, [16]byte{2}, 100, []byte{1})
+	p.SetStreamTerminated(true)
+	r.handle(p)
+	p = newTestPacket(t, 1, [16]byte{1}, 100, []byte{1})
+	p.SetStreamTerminated(true)
+	r.handle(p)
+
+	if univ := <-timeouts; univ != 1 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", univ, 1)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(timeouts) != 0 || len(r.sources) != 0 {
+		t.Errorf("Wrong state! %v additional timeouts and %v sources", len(timeouts), len(r.sources))
+	}
+}
+
+func TestGetActiveSources(t *testing.T) {
+	r := newReceiverSocket()
+	for i := byte(0); i < 3; i++ {
+		p := newTestPacket(t, 1, [16]byte{1}, 100, []byte{i})
+		p.SetSequence(10 - i) //the second and third packet are out of order
+		r.handle(p)
+	}
+	r.handle(newTestPacket(t, 1, [16]byte{2}, 150, []byte{1}))
+
+	sources := r.GetActiveSources(1)
+	if len(sources) != 2 {
+		t.Fatalf("Wrong output! Was: %v; Should've been 2 sources", sources)
+	}
+	for _, source := range sources {
+		if source.CID == [16]byte{1} && (source.PacketsReceived != 3 || source.SequenceErrors != 2 || source.Priority != 100) {
+			t.Errorf("Wrong output! Was: %+v", source)
+		}
+		if source.CID == [16]byte{2} && (source.PacketsReceived != 1 || source.SequenceErrors != 0 || source.Priority != 150) {
+			t.Errorf("Wrong output! Was: %+v", source)
+		}
+	}
+
+	//let the first source time out
+	r.sources[1][[16]byte{1}].lastSeen = time.Now().Add(-3 * time.Second)
+	if sources := r.GetActiveSources(1); len(sources) != 1 || sources[0].CID != [16]byte{2} {
+		t.Errorf("Wrong output! Was: %v; Should've been only the second source", sources)
+	}
+}
+
+func TestGetUniverseData(t *testing.T) {
+	r := newReceiverSocket()
+	if _, err := r.GetUniverseData(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a universe without data!")
+	}
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1, 2}))
+	data, err := r.GetUniverseData(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{1, 2}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{1, 2})
+	}
+	data[0] = 9 //the returned slice is a copy
+	if again, _ := r.GetUniverseData(1); !bytes.Equal(again, []byte{1, 2}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", again, []byte{1, 2})
+	}
+
+	//with a merger the merged data of all sources is returned
+	r.SetMerger(2, HTPMerger{})
+	r.handle(newTestPacket(t, 2, [16]byte{1}, 100, []byte{5, 0}))
+	r.handle(newTestPacket(t, 2, [16]byte{2}, 100, []byte{0, 7}))
+	if data, _ := r.GetUniverseData(2); !bytes.Equal(data, []byte{5, 7}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{5, 7})
+	}
+}
+
+func TestSetBufferDepth(t *testing.T) {
+	r := newReceiverSocket()
+	r.groups = &mockGroups{}
+	if err := r.SetBufferDepth(1, 0); err == nil {
+		t.Error("Err was nil! Should have been an error for depth 0!")
+	}
+	if err := r.SetBufferDepth(1, 100); err != nil {
+		t.Fatal(err)
+	}
+	packets, _ := r.ListenUniverse(1)
+	if cap(packets) != 100 {
+		t.Errorf("Wrong buffer depth! Was: %v; Should've been: %v", cap(packets), 100)
+	}
+	//nobody reads the channel, so the receiver must not block
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 150; i++ {
+			p := newTestPacket(t, 1, [16]byte{1}, 100, []byte{byte(i), 0})
+			p.SetSequence(byte(i))
+			r.handle(p)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The receiver blocked on a full channel!")
+	}
+	if len(packets) != 100 {
+		t.Fatalf("Wrong number of buffered packets! Was: %v; Should've been: %v", len(packets), 100)
+	}
+	//the oldest packets were dropped
+	if p := <-packets; p.Sequence() != 50 {
+		t.Errorf("Wrong oldest packet! Was: %v; Should've been: %v", p.Sequence(), 50)
+	}
+	if sources := r.GetActiveSources(1); len(sources) != 1 || sources[0].DroppedFrames != 50 {
+		t.Errorf("Wrong dropped frames! Was: %+v; Should've been: %v", sources, 50)
+	}
+
+	r.JoinUniverse(1)
+	if err := r.SetBufferDepth(1, 10); err == nil {
+		t.Error("Err was nil! Should have been an error for a joined universe!")
+	}
+}
+
+func TestLeaveUniverse(t *testing.T) {
+	r := newReceiverSocket()
+	groups := &mockGroups{}
+	r.groups = groups
+	packets, _ := r.ListenUniverse(1)
+	dmx, _ := r.ListenDMX(1)
+	unicast, _ := r.ListenDMX(2)
+	r.JoinUniverse(1)
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1}))
+	<-packets
+	<-dmx
+
+	if err := r.LeaveUniverse(1); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(groups.left, groups.joined) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", groups.left, groups.joined)
+	}
+	if _, ok := <-packets; ok {
+		t.Error("The packet channel was not closed!")
+	}
+	if _, ok := <-dmx; ok {
+		t.Error("The DMX channel was not closed!")
+	}
+	if sources := r.GetActiveSources(1); len(sources) != 0 {
+		t.Errorf("Sources were not removed: %v", sources)
+	}
+
+	//universe 2 was never joined, so only the channel is closed
+	if err := r.LeaveUniverse(2); err != nil {
+		t.Fatal(err)
+	}
+	if len(groups.left) != 1 {
+		t.Errorf("Wrong number of left groups! Was: %v; Should've been: %v", len(groups.left), 1)
+	}
+	if _, ok := <-unicast; ok {
+		t.Error("The DMX channel was not closed!")
+	}
+}
+
+func TestSubscribeAll(t *testing.T) {
+	r := newReceiverSocket()
+	all, _ := r.SubscribeAll()
+	for _, univ := range []uint16{1, 500, 63999} {
+		p := newTestPacket(t, univ, [16]byte{1}, 100, []byte{byte(univ)})
+		p.SetSourceName("source")
+		r.handle(p)
+	}
+	for _, univ := range []uint16{1, 500, 63999} {
+		frame := <-all
+		if frame.Universe != univ || !bytes.Equal(frame.Data, []byte{byte(univ), 0}) {
+			t.Errorf("Wrong output! Was: %v, %v; Should've been: %v, %v", frame.Universe, frame.Data, univ, []byte{byte(univ), 0})
+		}
+		if frame.Source.CID != [16]byte{1} || frame.Source.SourceName != "source" {
+			t.Errorf("Wrong source! Was: %+v", frame.Source)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	r := newReceiverSocket()
+	groups := &mockGroups{}
+	r.groups = groups
+	chs, err := r.Subscribe([]uint16{1, 0, 2, 1, 64000})
+	if err == nil {
+		t.Error("Subscribing to invalid universes did not return an error!")
+	}
+	if len(chs) != 2 || chs[1] == nil || chs[2] == nil {
+		t.Fatalf("Wrong channels! Was: %v; Should've been: %v", len(chs), 2)
+	}
+	should := []string{"239.255.0.1:5568", "239.255.0.2:5568"}
+	if !reflect.DeepEqual(groups.joined, should) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", groups.joined, should)
+	}
+
+	r.handle(newTestPacket(t, 2, [16]byte{1}, 100, []byte{2}))
+	r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{1}))
+	if data := <-chs[1]; !bytes.Equal(data, []byte{1, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{1, 0})
+	}
+	if data := <-chs[2]; !bytes.Equal(data, []byte{2, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{2, 0})
+	}
+
+	//already joined universes are not joined again
+	if _, err := r.Subscribe([]uint16{2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(groups.joined) != 2 {
+		t.Errorf("Wrong number of joined groups! Was: %v; Should've been: %v", len(groups.joined), 2)
+	}
+}
+
+func TestSetOnHighLatency(t *testing.T) {
+	r := newReceiverSocket()
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	delayed := make(chan SourceInfo, 10)
+	r.SetOnHighLatency(100*time.Millisecond, func(universe uint16, src SourceInfo) {
+		delayed <- src
+	})
+
+	p := newTestPacket(t, 1, [16]byte{1}, 100, []byte{1})
+	for _, gap := range []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond, 150 * time.Millisecond} {
+		now = now.Add(gap)
+		r.handle(p)
+		p.SequenceIncr()
+	}
+	select {
+	case src := <-delayed:
+		if src.CID != [16]byte{1} || !src.LastSeen.Equal(now) {
+			t.Errorf("Wrong output! Was: %+v", src)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The callback was not called!")
+	}
+	select {
+	case src := <-delayed:
+		t.Errorf("The callback was called for a gap below the threshold: %+v", src)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// handleAfterLoss lets the source of the packet time out and then handles the packet with the next
+// sequence number
+func handleAfterLoss(r *ReceiverSocket, p DataPacket) {
+	r.now = func() time.Time { return time.Now().Add(-3 * time.Second) }
+	r.handle(p)
+	r.now = time.Now
+	p.SequenceIncr()
+	p.SetData([]byte{2, 2})
+	r.handle(p)
+}
+
+func TestAutoResubscribe(t *testing.T) {
+	r := newReceiverSocket()
+	added := make(chan [16]byte, 10)
+	r.SetOnSourceAdded(func(universe uint16, cid [16]byte, sourceName string) {
+		added <- cid
+	})
+	ch, err := r.ListenDMX(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handleAfterLoss(r, newTestPacket(t, 1, [16]byte{1}, 100, []byte{1, 1}))
+	for _, shouldBe := range [][]byte{{1, 1}, {2, 2}} {
+		select {
+		case data := <-ch:
+			if !bytes.Equal(data, shouldBe) {
+				t.Errorf("Wrong output! Was: %v; Should've been: %v", data, shouldBe)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("The frame %v was not delivered!", shouldBe)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-added:
+		case <-time.After(time.Second):
+			t.Fatal("The OnSourceAdded callback was not called again after the source loss!")
+		}
+	}
+}
+
+func TestAutoResubscribeDisabled(t *testing.T) {
+	r := newReceiverSocket()
+	WithAutoResubscribe(false)(r)
+	r.groups = &mockGroups{}
+	ch, err := r.ListenDMX(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := newTestPacket(t, 1, [16]byte{1}, 100, []byte{1, 1})
+	handleAfterLoss(r, p)
+	<-ch
+	select {
+	case data := <-ch:
+		t.Errorf("The packet of the lost source was delivered: %v", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if blocked := r.Stats().BlockedPackets; blocked != 1 {
+		t.Errorf("Wrong number of blocked packets! Was: %v; Should've been: %v", blocked, 1)
+	}
+	//joining the universe again accepts the source
+	r.JoinUniverse(1)
+	p.SetSequence(2)
+	p.SetData([]byte{3, 3})
+	r.handle(p)
+	select {
+	case data := <-ch:
+		if !bytes.Equal(data, []byte{3, 3}) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{3, 3})
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The source was not accepted after joining the universe again!")
+	}
+}
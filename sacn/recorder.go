@@ -0,0 +1,130 @@
+package sacn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Recorder writes received packets together with their arrival time to an io.Writer.
+// Every packet is stored as an 8-byte unix timestamp in nanoseconds, followed by the 2-byte length of
+// the packet and the raw packet bytes. All numbers are big endian.
+// The recording can be played back with a Player.
+type Recorder struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewRecorder creates a new Recorder that writes to the given writer.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Record writes the given packet with the current time to the recording.
+func (rec *Recorder) Record(p DataPacket) error {
+	return rec.recordAt(p, time.Now())
+}
+
+// RecordFrom records every packet of the channel until it is closed. Use this together with
+// ReceiverSocket.ListenUniverse to record a universe. If a packet could not be written, the error
+// is returned immediately.
+func (rec *Recorder) RecordFrom(ch <-chan DataPacket) error {
+	for p := range ch {
+		if err := rec.Record(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rec *Recorder) recordAt(p DataPacket, at time.Time) error {
+	raw := p.Bytes()
+	buf := make([]byte, 10, 10+len(raw))
+	binary.BigEndian.PutUint64(buf[0:8], uint64(at.UnixNano()))
+	binary.BigEndian.PutUint16(buf[8:10], uint16(len(raw)))
+	buf = append(buf, raw...)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	_, err := rec.w.Write(buf)
+	return err
+}
+
+// readRecorded reads the next packet and its timestamp from a recording. Returns io.EOF if the
+// recording has ended.
+func readRecorded(r io.Reader) (DataPacket, time.Time, error) {
+	head := make([]byte, 10)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return DataPacket{}, time.Time{}, err
+	}
+	at := time.Unix(0, int64(binary.BigEndian.Uint64(head[0:8])))
+	raw := make([]byte, binary.BigEndian.Uint16(head[8:10]))
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return DataPacket{}, time.Time{}, fmt.Errorf("the recording is truncated: %v", err)
+	}
+	p, err := NewDataPacketRaw(raw)
+	return p, at, err
+}
+
+// Player plays back a recording that was created with a Recorder on a Transmitter.
+// The timing of the recording is preserved.
+type Player struct {
+	r     io.Reader
+	tx    *Transmitter
+	speed float64
+}
+
+// NewPlayer creates a new Player that reads the recording from r and sends the data via tx.
+func NewPlayer(r io.Reader, tx *Transmitter) *Player {
+	return &Player{r: r, tx: tx, speed: 1}
+}
+
+// SetSpeed sets the playback speed. 1 is the original speed, 2 plays twice as fast.
+// The factor has to be greater than 0.
+func (pl *Player) SetSpeed(factor float64) error {
+	if factor <= 0 {
+		return fmt.Errorf("the speed factor was %v and has to be greater than 0", factor)
+	}
+	pl.speed = factor
+	return nil
+}
+
+// Play plays back the whole recording and blocks until it has ended. The universes of the recording are
+// activated for the playback and deactivated afterwards. If one of them is already activated on the
+// transmitter, an error is returned when the universe is reached in the recording, because its channel
+// belongs to someone else. The universes that were activated so far are deactivated in that case.
+func (pl *Player) Play() error {
+	activated := make(map[uint16]chan<- []byte)
+	defer func() {
+		for _, ch := range activated {
+			close(ch)
+		}
+	}()
+	var start, first time.Time
+	for {
+		p, at, err := readRecorded(pl.r)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if first.IsZero() {
+			first, start = at, time.Now()
+		}
+		//wait until the packet is due
+		due := start.Add(time.Duration(float64(at.Sub(first)) / pl.speed))
+		time.Sleep(time.Until(due))
+
+		ch, ok := activated[p.Universe()]
+		if !ok {
+			ch, err = pl.tx.Activate(p.Universe())
+			if err != nil {
+				return err
+			}
+			activated[p.Universe()] = ch
+		}
+		ch <- append([]byte(nil), p.Data()...)
+	}
+}
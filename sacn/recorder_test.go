@@ -0,0 +1,86 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRecorderPlayer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rec := NewRecorder(buf)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		p := NewDataPacket()
+		p.SetUniverse(1)
+		p.SetData([]byte{byte(i + 1), 2})
+		if err := rec.recordAt(p, start.Add(time.Duration(i)*100*time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	player := NewPlayer(buf, &trans)
+	if err := player.SetSpeed(10); err != nil {
+		t.Fatal(err)
+	}
+	playStart := time.Now()
+	if err := player.Play(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(playStart); d < 15*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("The playback took %v; Should've been about 20ms", d)
+	}
+
+	//collect all played frames and skip the keep alive packets
+	frames := make([][]byte, 0)
+	for p := readTestPacket(t, conn); !p.StreamTerminated(); p = readTestPacket(t, conn) {
+		if len(p.Data()) == 2 && (len(frames) == 0 || !bytes.Equal(frames[len(frames)-1], p.Data())) {
+			frames = append(frames, append([]byte(nil), p.Data()...))
+		}
+	}
+	if len(frames) != 3 {
+		t.Fatalf("Wrong output! Was: %v; Should've been 3 frames", frames)
+	}
+	for i, frame := range frames {
+		if !bytes.Equal(frame, []byte{byte(i + 1), 2}) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", frame, []byte{byte(i + 1), 2})
+		}
+	}
+}
+
+func TestPlayerActivatedUniverse(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rec := NewRecorder(buf)
+	p := NewDataPacket()
+	p.SetUniverse(1)
+	p.SetData([]byte{1, 2})
+	if err := rec.recordAt(p, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	//the channel of the universe belongs to someone else, so it is not used for the playback
+	if err := NewPlayer(buf, &trans).Play(); err == nil {
+		t.Error("Playing on an activated universe should fail!")
+	}
+}
@@ -0,0 +1,79 @@
+package sacn
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReplayCID is the CID of the simulated source of ReceiverSocket.Replay.
+var ReplayCID = [16]byte{'s', 'a', 'c', 'n', '-', 'r', 'e', 'p', 'l', 'a', 'y'}
+
+// ReplayOption is used to configure a replay via ReceiverSocket.Replay.
+type ReplayOption func(c *replayConfig)
+
+type replayConfig struct {
+	terminate bool
+	priority  byte
+}
+
+// WithReplayTermination sends a packet with the stream terminated flag after the last frame, like a
+// source that stops sending. This invokes the timeout callback of the receiver.
+func WithReplayTermination() ReplayOption {
+	return func(c *replayConfig) {
+		c.terminate = true
+	}
+}
+
+// WithReplayPriority sets the priority of the simulated source. The default is 100.
+func WithReplayPriority(prio byte) ReplayOption {
+	return func(c *replayConfig) {
+		c.priority = prio
+	}
+}
+
+// Replay delivers the frames on the universe with the given rate in frames per second, as if a source
+// with the CID ReplayCID sent them. The packets get sequence numbers starting at 0 and pass all checks
+// of the receiver, like packets from the network. No socket is used, so this is meant for testing
+// consumers of the receiver without a real network. The receiver should not be started while replaying.
+// Replay blocks until all frames are delivered. Every frame has to be 0 to 512 bytes long.
+func (r *ReceiverSocket) Replay(universe uint16, frames [][]byte, fps float64, opts ...ReplayOption) error {
+	if err := checkListenUniverse(universe); err != nil {
+		return err
+	}
+	if fps <= 0 {
+		return fmt.Errorf("the frame rate was %v and has to be greater than 0", fps)
+	}
+	for i, frame := range frames {
+		if len(frame) > 512 {
+			return fmt.Errorf("frame %v was %v bytes long and therefore longer than 512 bytes", i, len(frame))
+		}
+	}
+	cfg := replayConfig{priority: 100}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	p := NewDataPacket()
+	p.SetCID(ReplayCID)
+	p.SetSourceName("replay")
+	p.SetUniverse(universe)
+	p.SetPriority(cfg.priority)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / fps))
+	defer ticker.Stop()
+	for i, frame := range frames {
+		if i > 0 {
+			<-ticker.C
+		}
+		p.SetData(frame)
+		r.handle(p.copy())
+		p.SequenceIncr()
+	}
+	if cfg.terminate {
+		if len(frames) > 0 {
+			<-ticker.C
+		}
+		p.SetStreamTerminated(true)
+		r.handle(p.copy())
+	}
+	return nil
+}
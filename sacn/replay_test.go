@@ -0,0 +1,81 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	r := newReceiverSocket()
+	packets, err := r.ListenUniverse(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeouts := make(chan uint16, 1)
+	r.SetTimeoutCallback(func(universe uint16) {
+		timeouts <- universe
+	})
+	//record the arrival time of every frame
+	var arrivals []time.Time
+	r.now = func() time.Time {
+		now := time.Now()
+		arrivals = append(arrivals, now)
+		return now
+	}
+	frames := make([][]byte, 10)
+	for i := range frames {
+		frames[i] = []byte{byte(i), 1, 2, 3}
+	}
+	const fps = 50
+	start := time.Now()
+	if err := r.Replay(3, frames, fps, WithReplayTermination()); err != nil {
+		t.Fatal(err)
+	}
+	//9 intervals between the frames and one before the termination
+	shouldTake := 10 * time.Second / fps
+	if took := time.Since(start); took < shouldTake*9/10 || took > shouldTake*11/10 {
+		t.Errorf("Wrong duration! Was: %v; Should've been: %v", took, shouldTake)
+	}
+
+	if len(arrivals) != len(frames) {
+		t.Fatalf("Wrong number of frames! Was: %v; Should've been: %v", len(arrivals), len(frames))
+	}
+	interval := 9 * time.Second / fps
+	if took := arrivals[9].Sub(arrivals[0]); took < interval*9/10 || took > interval*11/10 {
+		t.Errorf("Wrong interval! Was: %v; Should've been: %v", took, interval)
+	}
+	for i := range frames {
+		p := <-packets
+		if p.Sequence() != byte(i) {
+			t.Errorf("Wrong sequence! Was: %v; Should've been: %v", p.Sequence(), i)
+		}
+		if !bytes.Equal(p.Data(), frames[i]) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), frames[i])
+		}
+		if p.CID() != ReplayCID || p.Priority() != 100 {
+			t.Errorf("Wrong source! Was: %v, %v; Should've been: %v, %v", p.CID(), p.Priority(), ReplayCID, 100)
+		}
+	}
+	select {
+	case universe := <-timeouts:
+		if universe != 3 {
+			t.Errorf("Wrong universe! Was: %v; Should've been: %v", universe, 3)
+		}
+	case <-time.After(time.Second):
+		t.Error("The stream termination was not replayed!")
+	}
+}
+
+func TestReplayInvalid(t *testing.T) {
+	r := newReceiverSocket()
+	if err := r.Replay(0, [][]byte{{1}}, 10); err == nil {
+		t.Error("Replaying on universe 0 did not return an error!")
+	}
+	if err := r.Replay(1, [][]byte{{1}}, 0); err == nil {
+		t.Error("Replaying with 0 fps did not return an error!")
+	}
+	if err := r.Replay(1, [][]byte{make([]byte, 513)}, 10); err == nil {
+		t.Error("Replaying a frame with 513 bytes did not return an error!")
+	}
+}
@@ -0,0 +1,82 @@
+package sacn
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// cidRotation holds the current CID of a transmitter that uses WithCIDRotation
+type cidRotation struct {
+	interval time.Duration
+	mu       sync.Mutex
+	cid      [16]byte
+	stop     chan struct{} //closed by Close
+	stopOnce sync.Once
+}
+
+// WithCIDRotation replaces the CID of the transmitter with a new random CID every interval. All activated
+// universes send with the new CID immediately, only universes with a CID set via SetUniverseCID keep it.
+// This makes it harder to correlate the activity in long-running packet captures. Note that this breaks
+// E1.31, because receivers see every new CID as a new source and the old one as lost after 2.5s.
+// Only use this if you know that your receivers can handle it.
+func WithCIDRotation(interval time.Duration) TransmitterOption {
+	return func(t *Transmitter) {
+		t.rotation = &cidRotation{interval: interval, stop: make(chan struct{})}
+	}
+}
+
+// currentCID returns the CID that is used for new packets
+func (t *Transmitter) currentCID() [16]byte {
+	if t.rotation == nil {
+		return t.cid
+	}
+	t.rotation.mu.Lock()
+	defer t.rotation.mu.Unlock()
+	return t.rotation.cid
+}
+
+// rotateCIDs replaces the CID every interval. Runs until the transmitter is closed.
+func (t *Transmitter) rotateCIDs() {
+	ticker := time.NewTicker(t.rotation.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.rotation.stop:
+			return
+		case <-ticker.C:
+		}
+		cid, err := NewRandomCID()
+		if err != nil {
+			t.reportError(fmt.Errorf("the CID could not be rotated: %v", err))
+			continue
+		}
+		t.setCurrentCID(cid)
+	}
+}
+
+// stopRotation stops the goroutine of rotateCIDs. Does nothing if the CID is not rotated.
+func (t *Transmitter) stopRotation() {
+	if t.rotation == nil {
+		return
+	}
+	t.rotation.stopOnce.Do(func() {
+		close(t.rotation.stop)
+	})
+}
+
+// setCurrentCID sets the CID for new packets and the packets of all activated universes at once
+func (t *Transmitter) setCurrentCID(cid [16]byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rotation.mu.Lock()
+	t.rotation.cid = cid
+	t.rotation.mu.Unlock()
+	t.packetMu.Lock()
+	defer t.packetMu.Unlock()
+	for univ, packet := range t.master {
+		if _, ok := t.cids[univ]; !ok {
+			packet.SetCID(cid)
+		}
+	}
+}
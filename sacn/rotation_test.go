@@ -0,0 +1,66 @@
+package sacn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithCIDRotation(t *testing.T) {
+	initial := [16]byte{1}
+	trans, err := NewTransmitter("", initial, "test", WithCIDRotation(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	trans.SetUniverseCID(3, [16]byte{3})
+	for _, univ := range []uint16{1, 2, 3} {
+		ch, err := trans.Activate(univ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer close(ch)
+	}
+	for i := 0; trans.currentCID() == initial; i++ {
+		if i > 100 {
+			t.Fatal("The CID was not rotated!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	trans.mu.RLock()
+	defer trans.mu.RUnlock()
+	cid := trans.currentCID()
+	for _, univ := range []uint16{1, 2} {
+		if out := trans.master[univ].CID(); out != cid {
+			t.Errorf("Wrong CID on universe %v! Was: %v; Should've been: %v", univ, out, cid)
+		}
+	}
+	if out := trans.master[3].CID(); out != [16]byte{3} {
+		t.Errorf("The CID of the universe was rotated! Was: %v; Should've been: %v", out, [16]byte{3})
+	}
+}
+
+func TestWithCIDRotationInvalid(t *testing.T) {
+	if _, err := NewTransmitter("", [16]byte{1}, "test", WithCIDRotation(0)); err == nil {
+		t.Error("An interval of 0 did not return an error!")
+	}
+}
+
+func TestNewRandomCID(t *testing.T) {
+	first, err := NewRandomCID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewRandomCID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Errorf("Two random CIDs were equal: %v", first)
+	}
+	if first[6]>>4 != 4 || first[8]>>6 != 2 {
+		t.Errorf("The CID %v is not a version 4 UUID", FormatCID(first))
+	}
+}
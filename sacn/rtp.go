@@ -0,0 +1,87 @@
+package sacn
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"time"
+)
+
+const (
+	rtpVersion    = 2
+	rtpHeaderLen  = 12
+	rtpClockRate  = 90000 //the RTP timestamp is incremented with 90kHz, like for video streams
+	rtpMaxPayload = 127
+)
+
+// rtpState holds the RTP sequence number and the start of the RTP clock of one connection
+type rtpState struct {
+	sequence uint16
+	start    time.Time
+}
+
+// WithRTPTransport sends every sACN packet encapsulated in an RTP packet with the given payload type,
+// for networks that transport sACN via RTP, like RAVENNA. The fixed 12 byte RTP header is put in front of
+// the unchanged sACN packet. Every universe is its own RTP stream with a sequence number, a 90kHz
+// timestamp and an SSRC that is derived from the CID and the universe number. The payload type has to be
+// in range [0-127]. Receivers need WithRTPDecoding to read the packets.
+func WithRTPTransport(payloadType byte) TransmitterOption {
+	return func(t *Transmitter) {
+		t.rtp = true
+		t.rtpPayloadType = payloadType
+	}
+}
+
+// WithRTPDecoding removes the RTP header of every received packet before it is parsed, so packets of a
+// Transmitter with WithRTPTransport can be received. Packets without a valid RTP header are dropped.
+func WithRTPDecoding() ReceiverOption {
+	return func(r *ReceiverSocket) {
+		r.rtp = true
+	}
+}
+
+// rtpHeader returns the RTP header for the next packet of the connection. The caller has to hold the
+// send lock of the connection.
+func (c *universeConn) rtpHeader(payloadType byte, cid [16]byte, universe uint16) []byte {
+	if c.rtp.start.IsZero() {
+		c.rtp.start = time.Now()
+	}
+	header := make([]byte, rtpHeaderLen)
+	header[0] = rtpVersion << 6
+	header[1] = payloadType & 0x7f
+	binary.BigEndian.PutUint16(header[2:4], c.rtp.sequence)
+	c.rtp.sequence++
+	binary.BigEndian.PutUint32(header[4:8], uint32(time.Since(c.rtp.start)/(time.Second/rtpClockRate)))
+	binary.BigEndian.PutUint32(header[8:12], rtpSSRC(cid, universe))
+	return header
+}
+
+// rtpSSRC returns the synchronization source identifier of the universe of a source
+func rtpSSRC(cid [16]byte, universe uint16) uint32 {
+	h := fnv.New32a()
+	h.Write(cid[:])
+	h.Write([]byte{byte(universe >> 8), byte(universe)})
+	return h.Sum32()
+}
+
+// stripRTPHeader returns the payload of the RTP packet. The CSRC list, header extension and padding are
+// removed. Returns false if the packet is no valid RTP packet.
+func stripRTPHeader(b []byte) ([]byte, bool) {
+	if len(b) < rtpHeaderLen || b[0]>>6 != rtpVersion {
+		return nil, false
+	}
+	start := rtpHeaderLen + 4*int(b[0]&0x0f)
+	if b[0]&0x10 != 0 { //header extension
+		if len(b) < start+4 {
+			return nil, false
+		}
+		start += 4 + 4*int(binary.BigEndian.Uint16(b[start+2:start+4]))
+	}
+	end := len(b)
+	if b[0]&0x20 != 0 && end > 0 { //padding
+		end -= int(b[end-1])
+	}
+	if start > end {
+		return nil, false
+	}
+	return b[start:end], true
+}
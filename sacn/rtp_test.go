@@ -0,0 +1,110 @@
+package sacn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestRTPTransport(t *testing.T) {
+	if _, err := NewTransmitter("", [16]byte{1}, "test", WithRTPTransport(128)); err == nil {
+		t.Error("Err was nil! Should have been an error for an invalid payload type!")
+	}
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithRTPTransport(96))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetMulticast(1, true)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; trans.stats[1].snapshot().PacketsSent == 0; i++ {
+		if i > 100 {
+			t.Fatal("No keep alive packet was sent!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ch <- []byte{1, 2}
+	ch <- []byte{3, 4}
+	close(ch)
+	waitDeactivated(t, &trans, 1)
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	//one keep alive, two frames and three packets with the stream terminated flag
+	if len(sender.packets) != 6 {
+		t.Fatalf("Wrong number of packets! Was: %v; Should've been: %v", len(sender.packets), 6)
+	}
+	var lastTimestamp uint32
+	for i, raw := range sender.packets {
+		if version := raw[0] >> 6; version != 2 {
+			t.Errorf("Wrong version! Was: %v; Should've been: %v", version, 2)
+		}
+		if raw[1] != 96 {
+			t.Errorf("Wrong payload type! Was: %v; Should've been: %v", raw[1], 96)
+		}
+		if seq := binary.BigEndian.Uint16(raw[2:4]); seq != uint16(i) {
+			t.Errorf("Wrong sequence! Was: %v; Should've been: %v", seq, i)
+		}
+		timestamp := binary.BigEndian.Uint32(raw[4:8])
+		if timestamp < lastTimestamp {
+			t.Errorf("The timestamp decreased from %v to %v", lastTimestamp, timestamp)
+		}
+		lastTimestamp = timestamp
+		if ssrc := binary.BigEndian.Uint32(raw[8:12]); ssrc != rtpSSRC([16]byte{1}, 1) {
+			t.Errorf("Wrong SSRC! Was: %v; Should've been: %v", ssrc, rtpSSRC([16]byte{1}, 1))
+		}
+	}
+	p, err := NewDataPacketRaw(sender.packets[1][rtpHeaderLen:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p.Data(), []byte{1, 2}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), []byte{1, 2})
+	}
+
+	//the receiver removes the header
+	r := newReceiverSocket()
+	WithRTPDecoding()(r)
+	dmx, _ := r.ListenDMX(1)
+	r.handleRaw(sender.packets[1])
+	r.handleRaw(sender.packets[1][rtpHeaderLen:]) //no RTP packet
+	if out := <-dmx; !bytes.Equal(out, []byte{1, 2}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", out, []byte{1, 2})
+	}
+	select {
+	case out := <-dmx:
+		t.Errorf("A packet without RTP header was delivered: %v", out)
+	default:
+	}
+}
+
+func TestStripRTPHeader(t *testing.T) {
+	payload := []byte{1, 2, 3}
+	header := []byte{0x80, 96, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3}
+	tests := []struct {
+		name string
+		raw  []byte
+		ok   bool
+	}{
+		{"plain", append(append([]byte(nil), header...), payload...), true},
+		{"csrc", append(append([]byte{0x81, 96, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3}, 9, 9, 9, 9), payload...), true},
+		{"extension", append(append([]byte{0x90, 96, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3}, 0, 0, 0, 1, 9, 9, 9, 9), payload...), true},
+		{"padding", append(append([]byte{0xA0, 96, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3}, payload...), 0, 2), true},
+		{"version", append([]byte{0x40, 96, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3}, payload...), false},
+		{"short", header[:8], false},
+	}
+	for _, tt := range tests {
+		out, ok := stripRTPHeader(tt.raw)
+		if ok != tt.ok || (ok && !bytes.Equal(out, payload)) {
+			t.Errorf("%v: Wrong output! Was: %v, %v; Should've been: %v, %v", tt.name, out, ok, payload, tt.ok)
+		}
+	}
+}
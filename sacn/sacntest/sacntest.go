@@ -0,0 +1,285 @@
+/*
+Package sacntest provides compliance test suites for the sacn package. They can be used to verify that a
+configured Transmitter or ReceiverSocket behaves like the E1.31 specification requires.
+
+Every check is run as its own sub test, named after the clause of the specification it verifies, so
+failures can be attributed easily.
+*/
+package sacntest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+const (
+	// TransmitterUniverse is the universe that is activated by RunTransmitterCompliance.
+	TransmitterUniverse = 63999
+	// ReceiverUniverse is the first of the universes that are used by RunReceiverCompliance.
+	// The following three universes are used, too.
+	ReceiverUniverse = 63990
+)
+
+// RunTransmitterCompliance runs the compliance suite for a transmitter. The universe TransmitterUniverse
+// must not be activated on the transmitter. Its unicast destinations are overwritten by the suite.
+func RunTransmitterCompliance(t *testing.T, tx *sacn.Transmitter) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	univ := uint16(TransmitterUniverse)
+	if errs := tx.SetDestinationsWithPort(univ, []string{"127.0.0.1"}, port); errs != nil {
+		t.Fatal(errs)
+	}
+	ch, err := tx.Activate(univ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := false
+	defer func() {
+		if !closed {
+			close(ch)
+		}
+	}()
+	status, err := tx.GetUniverseStatus(univ)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("E1.31-6.2.7/universe range", func(t *testing.T) {
+		for _, invalid := range []uint16{0, 64000, 65535} {
+			if ch, err := tx.Activate(invalid); err == nil {
+				close(ch)
+				t.Errorf("universe %v could be activated", invalid)
+			}
+		}
+	})
+
+	var first []byte
+	t.Run("E1.31-4/PDU lengths", func(t *testing.T) {
+		first = readRaw(t, conn, status.KeepAlive)
+		checkLength(t, "root layer", first[16:18], len(first)-16)
+		checkLength(t, "framing layer", first[38:40], len(first)-38)
+		checkLength(t, "DMP layer", first[115:117], len(first)-115)
+		if count := int(first[123])<<8 | int(first[124]); count != len(first)-125 {
+			t.Errorf("property value count was %v, should have been %v", count, len(first)-125)
+		}
+	})
+	if first == nil {
+		t.FailNow()
+	}
+	p, err := sacn.NewDataPacketRaw(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("E1.31-5.6/CID", func(t *testing.T) {
+		if p.CID() == [16]byte{} {
+			t.Error("the CID is not set")
+		}
+	})
+
+	t.Run("E1.31-6.6.1/keep alive", func(t *testing.T) {
+		if status.KeepAlive <= 0 {
+			t.Fatalf("the keep alive interval was %v", status.KeepAlive)
+		}
+		//no data is sent, so the next packet has to be a keep alive packet
+		next := readPacket(t, conn, status.KeepAlive)
+		if next.Sequence() != p.Sequence()+1 {
+			t.Errorf("the keep alive packet had sequence %v, should have been %v", next.Sequence(), p.Sequence()+1)
+		}
+		p = next
+	})
+
+	t.Run("E1.31-6.7.2/sequence numbering", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			ch <- []byte{byte(i + 1)}
+			next := readPacket(t, conn, status.KeepAlive)
+			if next.Sequence() != p.Sequence()+1 {
+				t.Errorf("the sequence was %v, should have been %v", next.Sequence(), p.Sequence()+1)
+			}
+			p = next
+		}
+	})
+
+	t.Run("E1.31-6.7.1/stream termination", func(t *testing.T) {
+		close(ch)
+		closed = true
+		terminated := 0
+		for terminated < 3 {
+			next := readPacket(t, conn, status.KeepAlive)
+			if !next.StreamTerminated() {
+				continue //a keep alive packet that was sent before the termination
+			}
+			terminated++
+		}
+		for i := 0; tx.IsActivated(univ); i++ {
+			if i > 100 {
+				t.Fatal("the universe is still activated after the stream termination")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// RunReceiverCompliance runs the compliance suite for a receiver. The receiver has to be started and must
+// receive unicast packets that are sent to 127.0.0.1:5568. The suite uses the universes ReceiverUniverse to
+// ReceiverUniverse+3 and overwrites the timeout callback of the receiver.
+// Note that the source loss check waits for the E1.31 timeout of 2.5s.
+func RunReceiverCompliance(t *testing.T, rx *sacn.ReceiverSocket) {
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5568})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	timeouts := make(chan uint16, 16)
+	rx.SetTimeoutCallback(func(universe uint16) {
+		timeouts <- universe
+	})
+	sourceA := [16]byte{0xA}
+	sourceB := [16]byte{0xB}
+
+	t.Run("E1.31-6.2.3/source priority arbitration", func(t *testing.T) {
+		univ := uint16(ReceiverUniverse)
+		ch, err := rx.ListenDMX(univ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		send(t, conn, encodePacket(sourceA, univ, 100, 0, false, []byte{1}))
+		send(t, conn, encodePacket(sourceB, univ, 150, 0, false, []byte{2}))
+		send(t, conn, encodePacket(sourceA, univ, 100, 1, false, []byte{3}))
+		expectData(t, ch, []byte{1}, []byte{2})
+	})
+
+	t.Run("E1.31-6.7.2/sequence number validation", func(t *testing.T) {
+		univ := uint16(ReceiverUniverse + 1)
+		ch, err := rx.ListenDMX(univ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		send(t, conn, encodePacket(sourceA, univ, 100, 10, false, []byte{1}))
+		send(t, conn, encodePacket(sourceA, univ, 100, 5, false, []byte{2}))
+		send(t, conn, encodePacket(sourceA, univ, 100, 11, false, []byte{3}))
+		expectData(t, ch, []byte{1}, []byte{3})
+	})
+
+	t.Run("E1.31-6.7.1/stream terminated handling", func(t *testing.T) {
+		univ := uint16(ReceiverUniverse + 2)
+		send(t, conn, encodePacket(sourceA, univ, 100, 0, false, []byte{1}))
+		send(t, conn, encodePacket(sourceA, univ, 100, 1, true, []byte{1}))
+		expectTimeout(t, timeouts, univ, time.Second)
+	})
+
+	t.Run("E1.31-6.7.1/source loss detection", func(t *testing.T) {
+		univ := uint16(ReceiverUniverse + 3)
+		start := time.Now()
+		send(t, conn, encodePacket(sourceA, univ, 100, 0, false, []byte{1}))
+		expectTimeout(t, timeouts, univ, 6*time.Second)
+		if d := time.Since(start); d < 2500*time.Millisecond {
+			t.Errorf("the source was lost after %v, should have been after 2.5s", d)
+		}
+	})
+}
+
+// encodePacket creates the raw bytes of a data packet. This is independent of the encoding of the
+// sacn package, so it can be used to verify the parsing.
+func encodePacket(cid [16]byte, universe uint16, prio, sequ byte, terminated bool, data []byte) []byte {
+	length := 126 + len(data)
+	raw := make([]byte, length)
+	copy(raw, []byte{0, 0x10, 0, 0, 0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0, 0, 0})
+	putLength(raw[16:18], length-16)
+	raw[21] = 0x04 //VECTOR_ROOT_E131_DATA
+	copy(raw[22:38], cid[:])
+	putLength(raw[38:40], length-38)
+	raw[43] = 0x02 //VECTOR_E131_DATA_PACKET
+	copy(raw[44:107], "sacntest")
+	raw[108] = prio
+	raw[111] = sequ
+	if terminated {
+		raw[112] = 0x40
+	}
+	raw[113], raw[114] = byte(universe>>8), byte(universe)
+	putLength(raw[115:117], length-115)
+	raw[117] = 0x02 //VECTOR_DMP_SET_PROPERTY
+	raw[118] = 0xa1
+	raw[122] = 0x01
+	raw[123], raw[124] = byte((len(data)+1)>>8), byte(len(data)+1)
+	copy(raw[126:], data)
+	return raw
+}
+
+func putLength(b []byte, length int) {
+	b[0] = 0x70 | byte(length>>8&0x0F)
+	b[1] = byte(length)
+}
+
+func checkLength(t *testing.T, layer string, fal []byte, length int) {
+	if fal[0]&0xF0 != 0x70 {
+		t.Errorf("the flags of the %v were %#x, should have been 0x7", layer, fal[0]>>4)
+	}
+	if l := int(fal[0]&0x0F)<<8 | int(fal[1]); l != length {
+		t.Errorf("the length of the %v was %v, should have been %v", layer, l, length)
+	}
+}
+
+func readRaw(t *testing.T, conn *net.UDPConn, keepAlive time.Duration) []byte {
+	buf := make([]byte, 1144)
+	conn.SetReadDeadline(time.Now().Add(keepAlive + time.Second))
+	n, _, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatalf("no packet was received: %v", err)
+	}
+	return buf[:n]
+}
+
+func readPacket(t *testing.T, conn *net.UDPConn, keepAlive time.Duration) sacn.DataPacket {
+	p, err := sacn.NewDataPacketRaw(readRaw(t, conn, keepAlive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func send(t *testing.T, conn *net.UDPConn, raw []byte) {
+	if _, err := conn.Write(raw); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond) //keep the order of the packets
+}
+
+func expectData(t *testing.T, ch <-chan []byte, expected ...[]byte) {
+	for _, data := range expected {
+		select {
+		case got := <-ch:
+			if string(got) != string(data) {
+				t.Errorf("the received data was %v, should have been %v", got, data)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("the data %v was not received", data)
+		}
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("the data %v should have been discarded", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func expectTimeout(t *testing.T, timeouts <-chan uint16, universe uint16, wait time.Duration) {
+	deadline := time.After(wait)
+	for {
+		select {
+		case univ := <-timeouts:
+			if univ == universe {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("no timeout was reported for universe %v", universe)
+		}
+	}
+}
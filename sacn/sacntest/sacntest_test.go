@@ -0,0 +1,27 @@
+package sacntest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+func TestTransmitterCompliance(t *testing.T) {
+	tx, err := sacn.NewTransmitter("", [16]byte{1, 2, 3}, "compliance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.SetKeepAlive(100 * time.Millisecond)
+	RunTransmitterCompliance(t, &tx)
+}
+
+func TestReceiverCompliance(t *testing.T) {
+	rx, err := sacn.NewReceiverSocket("127.0.0.1", nil)
+	if err != nil {
+		t.Skipf("the sACN port is not available: %v", err)
+	}
+	rx.Start()
+	defer rx.Close()
+	RunReceiverCompliance(t, rx)
+}
@@ -0,0 +1,80 @@
+package sacn
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// SetSendBufferSize sets the size of the send buffer (SO_SNDBUF) of the udp socket of the universe in
+// bytes. A bigger buffer helps if a universe is sent at a high frame rate to many destinations.
+// The universe has to be activated, because the socket is created on activation. The size is applied
+// again if the socket is reopened after a network error. The operating system may adjust the value:
+// Linux doubles it for its own bookkeeping and caps it at net.core.wmem_max, macOS caps it at
+// kern.ipc.maxsockbuf. Use GetSendBufferSize to read the actual size.
+// If the PacketSender of the universe is not a real socket, ErrNotSupported is returned.
+func (t *Transmitter) SetSendBufferSize(universe uint16, bytes int) error {
+	if bytes < 1 {
+		return fmt.Errorf("the send buffer size was %v and has to be at least 1", bytes)
+	}
+	t.mu.Lock()
+	c, ok := t.conns[universe]
+	if !ok {
+		t.mu.Unlock()
+		return fmt.Errorf("the universe %v is not activated", universe)
+	}
+	t.sendBufferSizes[universe] = bytes
+	t.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return setSendBuffer(c.conn, bytes)
+}
+
+// GetSendBufferSize returns the size of the send buffer of the udp socket of the universe, as it was set
+// by the operating system. If the PacketSender of the universe is not a real socket, ErrNotSupported is
+// returned.
+func (t *Transmitter) GetSendBufferSize(universe uint16) (int, error) {
+	t.mu.RLock()
+	c, ok := t.conns[universe]
+	t.mu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("the universe %v is not activated", universe)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	raw, err := rawConn(c.conn)
+	if err != nil {
+		return 0, err
+	}
+	var size int
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		size, sockErr = getSockoptSndbuf(fd)
+	}); err != nil {
+		return 0, err
+	}
+	return size, sockErr
+}
+
+// setSendBuffer sets SO_SNDBUF of the socket of the connection
+func setSendBuffer(conn PacketSender, bytes int) error {
+	raw, err := rawConn(conn)
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = setSockoptSndbuf(fd, bytes)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// rawConn returns the raw socket of the connection, or ErrNotSupported if it has none
+func rawConn(conn PacketSender) (syscall.RawConn, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return sc.SyscallConn()
+}
@@ -0,0 +1,12 @@
+//go:build js || plan9
+// +build js plan9
+
+package sacn
+
+func setSockoptSndbuf(fd uintptr, bytes int) error {
+	return ErrNotSupported
+}
+
+func getSockoptSndbuf(fd uintptr) (int, error) {
+	return 0, ErrNotSupported
+}
@@ -0,0 +1,14 @@
+//go:build !windows && !js && !plan9
+// +build !windows,!js,!plan9
+
+package sacn
+
+import "syscall"
+
+func setSockoptSndbuf(fd uintptr, bytes int) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF, bytes)
+}
+
+func getSockoptSndbuf(fd uintptr) (int, error) {
+	return syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+}
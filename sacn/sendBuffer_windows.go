@@ -0,0 +1,17 @@
+package sacn
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func setSockoptSndbuf(fd uintptr, bytes int) error {
+	return syscall.SetsockoptInt(syscall.Handle(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF, bytes)
+}
+
+func getSockoptSndbuf(fd uintptr) (int, error) {
+	var size int32
+	length := int32(unsafe.Sizeof(size))
+	err := syscall.Getsockopt(syscall.Handle(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF, (*byte)(unsafe.Pointer(&size)), &length)
+	return int(size), err
+}
@@ -0,0 +1,192 @@
+package sacn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// closeTimeout is the time Close waits for the universes to send their packets with the stream
+// terminated flag
+const closeTimeout = 5 * time.Second
+
+// ErrTransmitterClosed is returned when a universe is activated on a transmitter after Close was called.
+var ErrTransmitterClosed = errors.New("sacn: the transmitter was closed")
+
+// AnnounceShutdown shuts the transmitter down as a whole source. First a universe discovery packet
+// with an empty universe list is sent, so receivers that discover the universes of the source notice
+// that it stopped. The packet is sent to the multicast address of the discovery universe and to the
+// destinations of all activated universes. Then all universes are deactivated, including the loops
+// and groups, and it is waited until all of them sent their packets with the stream terminated flag.
+// Only the first call does anything, further calls return nil. An error is returned if the discovery
+// packet could not be sent, the universes are deactivated anyway.
+// Like with CloseWithTimeout, frames that are written to the channels afterwards are discarded.
+func (t *Transmitter) AnnounceShutdown() error {
+	var err error
+	t.shutdown.Do(func() {
+		err = t.sendShutdownDiscovery()
+		t.CloseWithTimeout(context.Background())
+	})
+	return err
+}
+
+// Close shuts the transmitter down and releases all of its resources. Like AnnounceShutdown it sends an
+// empty universe discovery packet and deactivates all universes, so every universe sends its packets
+// with the stream terminated flag. Additionally the CID rotation and the watchdogs are stopped and the
+// sockets of ActivateSync are closed. Running reconnections are aborted. Universes that did not finish
+// their termination within closeTimeout are removed without it, see CloseWithTimeout. Close returns after
+// all goroutines of the universes exited and all sockets were closed. Afterwards no universe can be
+// activated anymore, Activate returns ErrTransmitterClosed. Further calls return nil.
+// Frames that are written to the channels afterwards are discarded. The functions of ActivateSync must not
+// be used after calling this method.
+func (t *Transmitter) Close() error {
+	t.mu.Lock()
+	atomic.StoreInt32(t.closed, 1)
+	t.mu.Unlock()
+
+	var err error
+	t.shutdown.Do(func() {
+		err = t.sendShutdownDiscovery()
+	})
+	//AnnounceShutdown may have been called before and universes activated afterwards
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if closeErr := t.CloseWithTimeout(ctx); err == nil {
+		err = closeErr
+	}
+	t.routines.Wait()
+
+	t.mu.Lock()
+	for univ, w := range t.watchdogs {
+		close(w.stop)
+		delete(t.watchdogs, univ)
+	}
+	syncConns := t.syncConns
+	t.syncConns = nil
+	t.mu.Unlock()
+	for _, conn := range syncConns {
+		conn.close()
+	}
+	t.stopRotation()
+	return err
+}
+
+// sendShutdownDiscovery sends a universe discovery packet without universes
+func (t *Transmitter) sendShutdownDiscovery() error {
+	p := DiscoveryPacket{SourceCID: t.currentCID(), SourceName: t.sourceName}
+	raw := p.getBytes()
+	conn, err := t.openConn(t.multicastIfi)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var sendErr error
+	send := func(addr *net.UDPAddr) {
+		if _, err := conn.WriteToUDP(raw, addr); err != nil {
+			sendErr = err
+		}
+	}
+	send(generateMulticast(discoveryUniverse))
+	sent := make(map[string]bool)
+	for _, univ := range t.GetActivated() {
+		dests, _ := t.destinationSnapshot(univ)
+		for _, dest := range dests {
+			if sent[dest.String()] {
+				continue
+			}
+			sent[dest.String()] = true
+			send(&dest)
+		}
+	}
+	return sendErr
+}
+
+// haltLoops stops all loops of ActivateLoop. Their universes are deactivated in the background.
+func (t *Transmitter) haltLoops() {
+	t.mu.Lock()
+	loops := make([]*frameLoop, 0, len(t.loops))
+	for univ, loop := range t.loops {
+		loops = append(loops, loop)
+		delete(t.loops, univ)
+	}
+	t.mu.Unlock()
+	for _, loop := range loops {
+		loop.halt()
+	}
+}
+
+// CloseWithTimeout deactivates all universes of the transmitter in parallel and waits until all of them
+// sent their packets with the stream terminated flag. Universes that belong to a UniverseGroup are
+// deactivated by closing their group and the loops of ActivateLoop are stopped. If the context is done before all universes finished, the
+// remaining universes are removed without sending the terminated packets and their connections are
+// closed. In that case an error is returned.
+// The channels of the universes are not closed, they still belong to the caller. Frames that are written
+// to them afterwards are discarded.
+func (t *Transmitter) CloseWithTimeout(ctx context.Context) error {
+	t.haltLoops()
+	t.mu.RLock()
+	done := make(map[uint16]chan struct{}, len(t.done))
+	for univ, d := range t.done {
+		done[univ] = d
+	}
+	conns := make([]*universeConn, 0, len(t.conns))
+	groups := make(map[*UniverseGroup]bool)
+	for univ, conn := range t.conns {
+		if g, ok := t.groups[univ]; ok {
+			groups[g] = true
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	t.mu.RUnlock()
+	for _, conn := range conns {
+		conn.deactivate()
+	}
+	for g := range groups {
+		go g.Close()
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range done {
+		wg.Add(1)
+		go func(d chan struct{}) {
+			defer wg.Done()
+			select {
+			case <-d:
+			case <-ctx.Done():
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	forced := make([]int, 0)
+	for univ, d := range done {
+		select {
+		case <-d:
+		default:
+			forced = append(forced, int(univ))
+		}
+	}
+	if len(forced) == 0 {
+		return nil
+	}
+	sort.Ints(forced)
+	conns = make([]*universeConn, 0, len(forced))
+	for _, univ := range forced {
+		t.mu.RLock()
+		conn, ok := t.conns[uint16(univ)]
+		t.mu.RUnlock()
+		if ok && t.removeUniverse(uint16(univ), conn) {
+			conns = append(conns, conn)
+		}
+	}
+	for _, conn := range conns {
+		conn.forceClose()
+	}
+	return fmt.Errorf("the universes %v were removed without termination: %v", forced, ctx.Err())
+}
@@ -0,0 +1,378 @@
+package sacn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowConn needs some time for every write
+type slowConn struct {
+	delay time.Duration
+}
+
+func (c *slowConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	time.Sleep(c.delay)
+	return len(b), nil
+}
+
+func (c *slowConn) Close() error {
+	return nil
+}
+
+func TestCloseWithTimeout(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &slowConn{delay: 20 * time.Millisecond}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	for univ := uint16(1); univ <= 10; univ++ {
+		trans.SetDestinations(univ, []string{"127.0.0.1"})
+		if _, err := trans.Activate(univ); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := trans.NewUniverseGroup([]uint16{11, 12}); err != nil {
+		t.Fatal(err)
+	}
+
+	//every universe needs 60ms for the termination, serially this would take at least 600ms
+	start := time.Now()
+	if err := trans.CloseWithTimeout(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("The shutdown was not parallel! It took %v", elapsed)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Universes are still activated: %v", activated)
+	}
+}
+
+func TestCloseWithTimeoutDeadline(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gate := make(chan struct{})
+	defer close(gate)
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &blockingConn{gate: gate}, nil
+	})
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	if _, err := trans.Activate(1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := trans.CloseWithTimeout(ctx); err == nil {
+		t.Error("Blocked universes should cause an error!")
+	}
+	if trans.IsActivated(1) {
+		t.Error("The blocked universe was not removed!")
+	}
+}
+
+func TestCloseWithTimeoutClosedChannel(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &slowConn{delay: 20 * time.Millisecond}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	trans.SetDestinations(2, []string{"127.0.0.1"})
+	ch1, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch2, err := trans.Activate(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//universe 1 is still sending its terminated packets, when CloseWithTimeout is called
+	close(ch1)
+	time.Sleep(5 * time.Millisecond)
+	if err := trans.CloseWithTimeout(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Universes are still activated: %v", activated)
+	}
+	//the channel still belongs to the caller: writing does not block and it can be closed
+	select {
+	case ch2 <- []byte{1}:
+	case <-time.After(time.Second):
+		t.Error("Writing to the channel of the deactivated universe blocked!")
+	}
+	close(ch2)
+}
+
+func TestCloseWithTimeoutLoop(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	stop, err := trans.ActivateLoop(1, [][]byte{{1}, {2}}, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := trans.CloseWithTimeout(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Universes are still activated: %v", activated)
+	}
+	if err := trans.UpdateLoopFrames(1, [][]byte{{3}}); err == nil {
+		t.Error("The loop was not stopped!")
+	}
+	//stopping the loop afterwards does nothing
+	stop()
+}
+
+func TestAnnounceShutdown(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	trans.SetDestinations(2, []string{"127.0.0.1"})
+	if _, err := trans.Activate(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.ActivateLoop(2, [][]byte{{1}, {2}}, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := trans.AnnounceShutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Universes are still activated: %v", activated)
+	}
+	sender.mu.Lock()
+	discovery, err := ParseDiscoveryPacket(sender.packets[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(discovery.Universes) != 0 || discovery.SourceCID != [16]byte{1} {
+		t.Errorf("Wrong output! Was: %v, %v; Should've been: %v, %v", discovery.Universes, discovery.SourceCID, []uint16{}, [16]byte{1})
+	}
+	if sender.addrs[0] != "239.255.250.214:5568" || sender.addrs[1] != "127.0.0.1:5568" {
+		t.Errorf("Wrong destinations of the discovery packet! Was: %v", sender.addrs[:2])
+	}
+	terminated := make(map[uint16]int)
+	for _, raw := range sender.packets {
+		if p, err := NewDataPacketRaw(raw); err == nil && p.StreamTerminated() {
+			terminated[p.Universe()]++
+		}
+	}
+	sent := len(sender.packets)
+	sender.mu.Unlock()
+	if terminated[1] != 3 || terminated[2] != 3 {
+		t.Errorf("Wrong number of terminated packets! Was: %v; Should've been: %v", terminated, map[uint16]int{1: 3, 2: 3})
+	}
+
+	//the transmitter is quiet and a second call does nothing
+	time.Sleep(20 * time.Millisecond)
+	if err := trans.AnnounceShutdown(); err != nil {
+		t.Fatal(err)
+	}
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	if len(sender.packets) != sent {
+		t.Errorf("Packets were sent after the shutdown! Was: %v; Should've been: %v", len(sender.packets), sent)
+	}
+}
+
+// closingSender records the packets and counts how often it was closed
+type closingSender struct {
+	recordingSender
+	closed int
+}
+
+func (s *closingSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed++
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithCIDRotation(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	senders := make([]*closingSender, 0)
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		s := &closingSender{}
+		senders = append(senders, s)
+		return s, nil
+	})
+	//the keep alive goroutines must not delay Close until their next packet
+	trans.keepAliveInterval = time.Hour
+	trans.SetMulticast(1, true)
+	trans.SetMulticast(2, true)
+	trans.SetWatchdog(1, time.Hour, WatchdogHold)
+	if _, err := trans.Activate(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.ActivateWithData(2, []byte{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.ActivateSync(3); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := trans.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close took too long! Was: %v", elapsed)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Universes are still activated: %v", activated)
+	}
+	terminated := make(map[uint16]int)
+	for _, s := range senders {
+		s.mu.Lock()
+		if s.closed != 1 {
+			t.Errorf("Wrong output! Was: %v closes; Should've been: %v", s.closed, 1)
+		}
+		for _, raw := range s.packets {
+			if p, err := NewDataPacketRaw(raw); err == nil && p.StreamTerminated() {
+				terminated[p.Universe()]++
+			}
+		}
+		s.mu.Unlock()
+	}
+	if len(senders) != 4 {
+		t.Errorf("Wrong number of sockets! Was: %v; Should've been: %v", len(senders), 4)
+	}
+	if terminated[1] != 3 || terminated[2] != 3 {
+		t.Errorf("Wrong number of terminated packets! Was: %v; Should've been: %v", terminated, map[uint16]int{1: 3, 2: 3})
+	}
+	trans.mu.RLock()
+	watchdogs := len(trans.watchdogs)
+	trans.mu.RUnlock()
+	if watchdogs != 0 {
+		t.Errorf("Wrong output! Was: %v watchdogs; Should've been: %v", watchdogs, 0)
+	}
+	select {
+	case <-trans.rotation.stop:
+	default:
+		t.Error("The CID rotation was not stopped!")
+	}
+
+	//nothing can be activated anymore and a second call does nothing
+	if _, err := trans.Activate(1); err != ErrTransmitterClosed {
+		t.Errorf("Wrong error! Was: %v; Should've been: %v", err, ErrTransmitterClosed)
+	}
+	if _, err := trans.ActivateSync(4); err != ErrTransmitterClosed {
+		t.Errorf("Wrong error! Was: %v; Should've been: %v", err, ErrTransmitterClosed)
+	}
+	if err := trans.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCloseWhileReconnecting(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the first connection fails every write and all later connections can not be opened
+	mu := &sync.Mutex{}
+	failures, written, opened := 1<<30, 0, 0
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened++
+		if opened > 1 {
+			return nil, errors.New("no network")
+		}
+		return &failingConn{mu: mu, failures: &failures, written: &written}, nil
+	})
+	trans.keepAliveInterval = 10 * time.Millisecond
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	if _, err := trans.Activate(1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+	if err := trans.Close(); err == nil {
+		t.Error("Err was nil! Should have been an error, because the discovery packet could not be sent!")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close took too long! Was: %v", elapsed)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Universes are still activated: %v", activated)
+	}
+	//the reconnection was aborted
+	mu.Lock()
+	attempts := opened
+	mu.Unlock()
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if opened != attempts {
+		t.Errorf("Reconnection continued after Close! Was: %v attempts; Should've been: %v", opened, attempts)
+	}
+}
+
+func TestCloseWhileSending(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//every write fails, so every SendNow starts a reconnection
+	mu := &sync.Mutex{}
+	failures, written := 1<<30, 0
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &failingConn{mu: mu, failures: &failures, written: &written}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	if _, err := trans.Activate(1); err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			trans.SendNow(1)
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	trans.Close()
+	close(stop)
+	<-done
+}
@@ -0,0 +1,45 @@
+package sacn
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Sniff writes every raw packet the receiver gets to w, regardless if it is a valid sACN packet or
+// passes any filter. This is meant for debugging in the field. Every packet is written as an 8-byte
+// length, followed by the 8-byte unix timestamp of its arrival in nanoseconds and the raw bytes of the
+// packet. All numbers are big endian. Multiple sniffers can be used at the same time.
+// Writing to w blocks the receiving, so w should be fast. Errors of w are ignored.
+// Call the returned function to stop sniffing. After it returned, nothing is written to w anymore.
+func (r *ReceiverSocket) Sniff(w io.Writer) (cancel func()) {
+	r.sniffMu.Lock()
+	defer r.sniffMu.Unlock()
+	if r.sniffers == nil {
+		r.sniffers = make(map[int]io.Writer)
+	}
+	id := r.nextSniffer
+	r.nextSniffer++
+	r.sniffers[id] = w
+	return func() {
+		r.sniffMu.Lock()
+		defer r.sniffMu.Unlock()
+		delete(r.sniffers, id)
+	}
+}
+
+// sniff writes the raw packet to all sniffers
+func (r *ReceiverSocket) sniff(raw []byte) {
+	r.sniffMu.Lock()
+	defer r.sniffMu.Unlock()
+	//an empty packet is passed after a read timeout of the socket
+	if len(raw) == 0 || len(r.sniffers) == 0 {
+		return
+	}
+	record := make([]byte, 16, 16+len(raw))
+	binary.BigEndian.PutUint64(record[0:8], uint64(len(raw)))
+	binary.BigEndian.PutUint64(record[8:16], uint64(r.now().UnixNano()))
+	record = append(record, raw...)
+	for _, w := range r.sniffers {
+		w.Write(record)
+	}
+}
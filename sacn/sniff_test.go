@@ -0,0 +1,53 @@
+package sacn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestSniff(t *testing.T) {
+	r := newReceiverSocket()
+	now := time.Now()
+	r.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	var first, second bytes.Buffer
+	cancelFirst := r.Sniff(&first)
+	cancelSecond := r.Sniff(&second)
+	p1 := newTestPacket(t, 1, [16]byte{1}, 100, []byte{1})
+	p2 := newTestPacket(t, 2, [16]byte{2}, 100, []byte{2, 3})
+	packets := [][]byte{p1.Bytes(), []byte("not an sACN packet"), p2.Bytes()}
+	for _, raw := range packets {
+		r.handleRaw(raw)
+	}
+	cancelFirst()
+	r.handleRaw(packets[0])
+	cancelSecond()
+	r.handleRaw(packets[0])
+
+	check := func(buf *bytes.Buffer, shouldBe [][]byte) {
+		var last uint64
+		for i, raw := range shouldBe {
+			if buf.Len() < 16 {
+				t.Fatalf("Record %v is missing!", i)
+			}
+			length := binary.BigEndian.Uint64(buf.Next(8))
+			timestamp := binary.BigEndian.Uint64(buf.Next(8))
+			if timestamp <= last {
+				t.Errorf("The timestamps are not increasing! Was: %v; Last: %v", timestamp, last)
+			}
+			last = timestamp
+			if out := buf.Next(int(length)); !bytes.Equal(out, raw) {
+				t.Errorf("Wrong output! Was: %v; Should've been: %v", out, raw)
+			}
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%v bytes were written after cancelling!", buf.Len())
+		}
+	}
+	check(&first, packets)
+	check(&second, append(packets, packets[0]))
+}
@@ -0,0 +1,71 @@
+package sacn
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// transmitterState is the format of SaveState. It is encoded with encoding/gob.
+type transmitterState struct {
+	Universes map[uint16]UniverseConfig //the settings of the activated universes
+	Data      map[uint16][]byte         //the last DMX data of the activated universes
+}
+
+// SaveState writes the settings and the last DMX data of all activated universes to w, so the output
+// can be restored via RestoreState after a restart of the process, without blacking out the fixtures.
+// The settings of the transmitter itself, like its CID, are not saved, see ExportConfig for those.
+// The format is encoded with encoding/gob and is only meant to be read by RestoreState.
+func (t *Transmitter) SaveState(w io.Writer) error {
+	cfg := t.ExportConfig()
+	state := transmitterState{
+		Universes: make(map[uint16]UniverseConfig),
+		Data:      make(map[uint16][]byte),
+	}
+	for _, univ := range t.GetActivated() {
+		var data []byte
+		if !t.withMaster(univ, func(p *DataPacket) {
+			data = append([]byte(nil), p.Data()...)
+		}) {
+			continue //the universe was deactivated in the meantime
+		}
+		state.Universes[univ] = cfg.Universes[univ]
+		state.Data[univ] = data
+	}
+	return gob.NewEncoder(w).Encode(state)
+}
+
+// RestoreState reads a state that was written by SaveState and activates all saved universes with their
+// settings and their saved DMX data, like ActivateWithData. The channels of the universes can be
+// retrieved via Channel. An error is returned if the state could not be read, one of the universes is
+// already activated or the settings of one of the universes are invalid, in that case nothing is changed.
+// If the activation of a universe fails, the universes that were activated before stay activated.
+func (t *Transmitter) RestoreState(r io.Reader) error {
+	var state transmitterState
+	if err := gob.NewDecoder(r).Decode(&state); err != nil {
+		return err
+	}
+	universes := make([]int, 0, len(state.Data))
+	for univ := range state.Data {
+		if t.IsActivated(univ) {
+			return fmt.Errorf("the universe %v is already activated", univ)
+		}
+		if err := checkUniverseConfig(univ, state.Universes[univ]); err != nil {
+			return err
+		}
+		universes = append(universes, int(univ))
+	}
+	sort.Ints(universes)
+	for _, univ := range universes {
+		if err := t.applyUniverseConfig(uint16(univ), state.Universes[uint16(univ)]); err != nil {
+			return err
+		}
+	}
+	for _, univ := range universes {
+		if _, err := t.ActivateWithData(uint16(univ), state.Data[uint16(univ)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package sacn
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+)
+
+func TestSaveState(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	trans.SetMulticast(2, true)
+	if err := trans.SetUniverseSourceName(2, "second"); err != nil {
+		t.Fatal(err)
+	}
+	ch1, err := trans.ActivateWithData(1, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch1)
+	ch2, err := trans.ActivateWithData(2, []byte{4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch2)
+	var buf bytes.Buffer
+	if err := trans.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := NewTransmitter("", [16]byte{2}, "restored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	restored.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	restored.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	saved := buf.Bytes()
+	if err := restored.RestoreState(bytes.NewReader(saved)); err != nil {
+		t.Fatal(err)
+	}
+	for _, univ := range []uint16{1, 2} {
+		ch, ok := restored.Channel(univ)
+		if !ok {
+			t.Fatalf("Universe %v was not activated!", univ)
+		}
+		defer close(ch)
+		for i := 0; restored.stats[univ].snapshot().PacketsSent == 0; i++ {
+			if i > 100 {
+				t.Fatalf("No keep alive packet was sent on universe %v!", univ)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if err := restored.RestoreState(bytes.NewReader(saved)); err == nil {
+		t.Error("Restoring activated universes should fail!")
+	}
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	should := map[uint16]struct {
+		addr string
+		name string
+		data []byte
+	}{
+		1: {"127.0.0.1:5568", "restored", []byte{1, 2, 3, 0}},
+		2: {"239.255.0.2:5568", "second", []byte{4, 5}},
+	}
+	if len(sender.packets) != 2 {
+		t.Fatalf("Wrong number of packets! Was: %v; Should've been: %v", len(sender.packets), 2)
+	}
+	for i, raw := range sender.packets {
+		p, err := NewDataPacketRaw(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := should[p.Universe()]
+		if sender.addrs[i] != s.addr || p.SourceName() != s.name || !bytes.Equal(p.Data(), s.data) {
+			t.Errorf("Wrong output on universe %v! Was: %v, %v, %v; Should've been: %v, %v, %v",
+				p.Universe(), sender.addrs[i], p.SourceName(), p.Data(), s.addr, s.name, s.data)
+		}
+	}
+}
+
+func TestRestoreStateInvalid(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := transmitterState{
+		Universes: map[uint16]UniverseConfig{
+			1: {Destinations: []string{"127.0.0.1:5568"}},
+			2: {CID: "invalid"},
+		},
+		Data: map[uint16][]byte{1: {1}, 2: {2}},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.RestoreState(&buf); err == nil {
+		t.Fatal("Restoring an invalid CID should fail!")
+	}
+	//nothing was changed, also not the valid universe
+	if dests := trans.Destinations(1); len(dests) != 0 {
+		t.Errorf("Wrong output! Was: %v; Should've been no destinations", dests)
+	}
+	if activated := trans.GetActivated(); len(activated) != 0 {
+		t.Errorf("Wrong output! Was: %v; Should've been no activated universes", activated)
+	}
+}
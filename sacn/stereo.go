@@ -0,0 +1,125 @@
+package sacn
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// stereoPair holds the state of two universes that are synchronized, see ActivateStereo
+type stereoPair struct {
+	left, right uint16
+	mu          sync.Mutex
+	leftSent    bool //true, if a packet of the left universe was sent since the last sync packet
+	rightSent   bool
+	sequence    byte //the sequence number of the sync packets
+}
+
+// ActivateStereo activates the two universes as a synchronized pair, e.g. for the left and right channel
+// of audio driven DMX data. Both universes are activated like with Activate, but their packets carry the
+// left universe as synchronization address. After a packet of each universe was sent, a synchronization
+// packet is sent on the left universe, so a compliant receiver buffers the data of both universes and
+// applies it at the same time. The sync packets are sent to the multicast address of the left universe,
+// if multicast is used for one of the universes, and to the destinations of both universes.
+// If one of the universes could not be activated, none of them is activated.
+// If one of the universes gets deactivated, the other one is sent without synchronization.
+func (t *Transmitter) ActivateStereo(leftUniverse, rightUniverse uint16) (left, right chan<- []byte, err error) {
+	if leftUniverse == rightUniverse {
+		return nil, nil, fmt.Errorf("the left and right universe are both %v", leftUniverse)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	left, err = t.activateLocked(leftUniverse, make([]byte, 512), 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	right, err = t.activateLocked(rightUniverse, make([]byte, 512), 0)
+	if err != nil {
+		//nothing was sent on the left universe yet, because the goroutines wait for the lock
+		conn := t.conns[leftUniverse]
+		close(t.universes[leftUniverse])
+		t.removeUniverseLocked(leftUniverse)
+		conn.forceClose()
+		return nil, nil, err
+	}
+	pair := &stereoPair{left: leftUniverse, right: rightUniverse}
+	t.packetMu.Lock()
+	t.master[leftUniverse].SetSyncAddress(leftUniverse)
+	t.master[rightUniverse].SetSyncAddress(leftUniverse)
+	t.packetMu.Unlock()
+	t.stereoPairs[leftUniverse] = pair
+	t.stereoPairs[rightUniverse] = pair
+	return left, right, nil
+}
+
+// sent marks the universe as sent and returns true, if both universes were sent since the last sync
+// packet. In this case the sequence number for the next sync packet is returned.
+func (p *stereoPair) sent(universe uint16) (bool, byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if universe == p.left {
+		p.leftSent = true
+	} else {
+		p.rightSent = true
+	}
+	if !p.leftSent || !p.rightSent {
+		return false, 0
+	}
+	p.leftSent, p.rightSent = false, false
+	sequence := p.sequence
+	p.sequence++
+	return true, sequence
+}
+
+// sendStereoSync sends a sync packet for the pair, if both universes were sent since the last one.
+// The packet is written with the connection of the universe that was sent last.
+func (t *Transmitter) sendStereoSync(server *universeConn, pair *stereoPair, universe uint16, cid [16]byte) {
+	ok, sequence := pair.sent(universe)
+	if !ok {
+		return
+	}
+	raw := syncPacketBytes(cid, sequence, pair.left)
+	var sendErr error
+	send := func(addr *net.UDPAddr) {
+		if _, err := server.write(raw, addr); err != nil {
+			sendErr = err
+		}
+	}
+	if t.IsMulticast(pair.left) || t.IsMulticast(pair.right) {
+		send(generateMulticast(pair.left))
+	}
+	sent := make(map[string]bool)
+	for _, univ := range []uint16{pair.left, pair.right} {
+		dests, _ := t.destinationSnapshot(univ)
+		for _, dest := range dests {
+			if sent[dest.String()] {
+				continue
+			}
+			sent[dest.String()] = true
+			send(&dest)
+		}
+	}
+	if sendErr != nil {
+		t.reportError(sendErr)
+	}
+}
+
+// removeStereoPairLocked removes the pair of the universe, so the other universe is sent without
+// synchronization. The caller has to hold the lock.
+func (t *Transmitter) removeStereoPairLocked(universe uint16) {
+	pair, ok := t.stereoPairs[universe]
+	if !ok {
+		return
+	}
+	delete(t.stereoPairs, pair.left)
+	delete(t.stereoPairs, pair.right)
+	other := pair.left
+	if universe == pair.left {
+		other = pair.right
+	}
+	if master, ok := t.master[other]; ok {
+		t.packetMu.Lock()
+		master.SetSyncAddress(0)
+		t.packetMu.Unlock()
+	}
+}
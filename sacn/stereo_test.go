@@ -0,0 +1,86 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestActivateStereo(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	trans.SetDestinationsWithPort(2, []string{"127.0.0.1"}, port)
+	if _, _, err := trans.ActivateStereo(3, 3); err == nil {
+		t.Error("Activating the same universe as pair should fail!")
+	}
+	left, right, err := trans.ActivateStereo(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := trans.ActivateStereo(3, 2); err == nil || trans.IsActivated(3) {
+		t.Error("Activating a pair with an already activated universe should fail!")
+	}
+
+	buf := make([]byte, 638)
+	//the first keep alive packets of both universes and their sync packet
+	for i := 0; i < 3; i++ {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		if _, _, err := conn.ReadFromUDP(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	left <- []byte{1}
+	right <- []byte{2}
+	data := make(map[uint16][]byte)
+	for len(data) < 2 {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == syncPacketLength {
+			t.Fatal("The sync packet was sent before both universes!")
+		}
+		p, err := NewDataPacketRaw(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.SyncAddress() != 1 {
+			t.Errorf("Wrong sync address on universe %v! Was: %v; Should've been: %v", p.Universe(), p.SyncAddress(), 1)
+		}
+		data[p.Universe()] = p.Data()
+	}
+	if !bytes.Equal(data[1], []byte{1, 0}) || !bytes.Equal(data[2], []byte{2, 0}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, map[uint16][]byte{1: {1, 0}, 2: {2, 0}})
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should := syncPacketBytes([16]byte{1}, 1, 1); !bytes.Equal(buf[:n], should) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", buf[:n], should)
+	}
+
+	//after the right universe was deactivated, the left one is sent without synchronization
+	close(right)
+	waitDeactivated(t, &trans, 2)
+	left <- []byte{3}
+	for {
+		p := readTestPacket(t, conn)
+		if p.Universe() == 1 {
+			if p.SyncAddress() != 0 {
+				t.Errorf("Wrong sync address! Was: %v; Should've been: %v", p.SyncAddress(), 0)
+			}
+			break
+		}
+	}
+	close(left)
+}
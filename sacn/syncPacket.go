@@ -0,0 +1,90 @@
+package sacn
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	vectorE131ExtendedSynchronization = 1 //VECTOR_E131_EXTENDED_SYNCHRONIZATION
+	syncPacketLength                  = 49
+)
+
+// syncPacketBytes returns a synchronization packet (E1.31 6.3) in the wire format
+func syncPacketBytes(cid [16]byte, sequence byte, syncUniverse uint16) []byte {
+	raw := make([]byte, 0, syncPacketLength)
+	raw = append(raw, constHeader...)
+	fal := calculateFal(syncPacketLength - 16)
+	raw = append(raw, fal[:]...)
+	raw = append(raw, getAsBytes32(vectorRootE131Extended)...)
+	raw = append(raw, cid[:]...)
+	fal = calculateFal(syncPacketLength - 38)
+	raw = append(raw, fal[:]...)
+	raw = append(raw, getAsBytes32(vectorE131ExtendedSynchronization)...)
+	raw = append(raw, sequence)
+	raw = append(raw, getAsBytes16(syncUniverse)...)
+	raw = append(raw, 0, 0) //reserved
+	return raw
+}
+
+// ActivateSync registers the given universe as synchronization universe and returns a function that
+// sends a synchronization packet on it. Receivers that get data packets with this universe as sync
+// address hold the data back until the synchronization packet arrives, so multiple universes are
+// updated at the same time. The synchronization packets are sent to the destinations and the multicast
+// setting of the sync universe and have their own sequence numbering.
+// The sync universe can not be activated for data and has to be in range [1-63999].
+func (t *Transmitter) ActivateSync(syncUniverse uint16) (func() error, error) {
+	if !IsValidDataUniverse(syncUniverse) {
+		return nil, fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", syncUniverse)
+	}
+	t.mu.Lock()
+	if atomic.LoadInt32(t.closed) != 0 {
+		t.mu.Unlock()
+		return nil, ErrTransmitterClosed
+	}
+	if _, ok := t.universes[syncUniverse]; ok || t.syncUniverses[syncUniverse] {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("the given universe %v is already activated", syncUniverse)
+	}
+	conn, err := t.listen(t.bind)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, err
+	}
+	serv := newUniverseConn(conn)
+	t.syncUniverses[syncUniverse] = true
+	t.syncConns = append(t.syncConns, serv)
+	cid := t.currentCID()
+	if universeCID, ok := t.cids[syncUniverse]; ok {
+		cid = universeCID
+	}
+	t.mu.Unlock()
+
+	var mu sync.Mutex
+	var sequence byte
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		raw := syncPacketBytes(cid, sequence, syncUniverse)
+		sequence++
+		var sendErr error
+		send := func(addr *net.UDPAddr) {
+			if _, err := serv.write(raw, addr); err != nil {
+				sendErr = err
+			}
+		}
+		if t.IsMulticast(syncUniverse) {
+			send(generateMulticast(syncUniverse))
+		}
+		dests, _ := t.destinationSnapshot(syncUniverse)
+		for _, dest := range dests {
+			send(&dest)
+		}
+		if sendErr != nil {
+			t.reportError(sendErr)
+		}
+		return sendErr
+	}, nil
+}
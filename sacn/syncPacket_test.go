@@ -0,0 +1,54 @@
+package sacn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestActivateSync(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1, 2, 3}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetDestinationsWithPort(7000, []string{"127.0.0.1"}, port)
+	sendSync, err := trans.ActivateSync(7000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.Activate(7000); err == nil {
+		t.Error("Activating a sync universe for data should fail!")
+	}
+	if _, err := trans.ActivateSync(7000); err == nil {
+		t.Error("Activating a sync universe twice should fail!")
+	}
+
+	buf := make([]byte, 638)
+	for sequence := byte(0); sequence < 2; sequence++ {
+		if err := sendSync(); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shouldBe := []byte{
+			0x00, 0x10, 0x00, 0x00, 0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00, //preamble, ACN identifier
+			0x70, 0x21, //root flags & length
+			0x00, 0x00, 0x00, 0x08, //VECTOR_ROOT_E131_EXTENDED
+			1, 2, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, //CID
+			0x70, 0x0b, //framing flags & length
+			0x00, 0x00, 0x00, 0x01, //VECTOR_E131_EXTENDED_SYNCHRONIZATION
+			sequence,
+			0x1b, 0x58, //sync address 7000
+			0x00, 0x00, //reserved
+		}
+		if !bytes.Equal(buf[:n], shouldBe) {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", buf[:n], shouldBe)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Package tcp transports sACN packets over TCP, for installations that route sACN over links where the
+packet loss of UDP is not acceptable, e.g. WAN links.
+
+Every packet is sent unchanged as on the UDP port 5568, prefixed with its length as 4 byte unsigned
+integer in network byte order. A TCPTransmitter connects to one TCPReceiver, a TCPReceiver accepts any
+number of transmitters.
+*/
+package tcp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+const (
+	reconnectMinBackoff = 50 * time.Millisecond
+	reconnectMaxBackoff = 5 * time.Second
+	maxFrameLen         = 1144 //the largest sACN packet, a full universe discovery packet
+	packetBufferSize    = 64
+)
+
+// TCPTransmitter sends packets over a TCP connection. Use NewTCPTransmitter to create one and Dial to
+// connect it.
+type TCPTransmitter struct {
+	mu           sync.Mutex
+	addr         string
+	conn         net.Conn //nil while not connected
+	reconnecting bool
+	closed       bool
+}
+
+// NewTCPTransmitter creates a transmitter that is not connected yet.
+func NewTCPTransmitter() *TCPTransmitter {
+	return &TCPTransmitter{}
+}
+
+// Dial connects the transmitter to the TCPReceiver at the given address, e.g. "192.168.1.2:5568".
+// If the connection is lost later, the transmitter reconnects automatically with an exponential backoff
+// between 50ms and 5s. Packets that are sent while it is not connected are not transmitted.
+func (t *TCPTransmitter) Dial(addr string) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		conn.Close()
+		return fmt.Errorf("the transmitter is closed")
+	}
+	if t.conn != nil {
+		t.conn.Close()
+	}
+	t.addr = addr
+	t.conn = conn
+	return nil
+}
+
+// Send writes the packet to the connection. An error is returned if the transmitter is not connected or
+// the packet could not be written. In the latter case the transmitter starts reconnecting.
+func (t *TCPTransmitter) Send(p sacn.DataPacket) error {
+	raw := p.Bytes()
+	frame := make([]byte, 4, 4+len(raw))
+	binary.BigEndian.PutUint32(frame, uint32(len(raw)))
+	frame = append(frame, raw...)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return fmt.Errorf("the transmitter is not connected")
+	}
+	if _, err := t.conn.Write(frame); err != nil {
+		t.conn.Close()
+		t.conn = nil
+		if !t.reconnecting && !t.closed {
+			t.reconnecting = true
+			go t.reconnect()
+		}
+		return err
+	}
+	return nil
+}
+
+// reconnect dials the address until it succeeds or the transmitter is closed
+func (t *TCPTransmitter) reconnect() {
+	backoff := reconnectMinBackoff
+	for {
+		time.Sleep(backoff)
+		t.mu.Lock()
+		addr, closed := t.addr, t.closed
+		t.mu.Unlock()
+		if closed {
+			return
+		}
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			t.mu.Lock()
+			t.reconnecting = false
+			if t.closed {
+				conn.Close()
+			} else {
+				t.conn = conn
+			}
+			t.mu.Unlock()
+			return
+		}
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
+// Connected returns true if the transmitter currently has a connection.
+func (t *TCPTransmitter) Connected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn != nil
+}
+
+// Close closes the connection and stops reconnecting.
+func (t *TCPTransmitter) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// TCPReceiver accepts connections of TCPTransmitters and delivers their packets. Use NewTCPReceiver to
+// create one and Listen to start receiving.
+type TCPReceiver struct {
+	packets  chan sacn.DataPacket
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	listener net.Listener
+	clients  map[net.Conn]bool
+	closed   bool
+}
+
+// NewTCPReceiver creates a receiver that does not listen yet.
+func NewTCPReceiver() *TCPReceiver {
+	return &TCPReceiver{
+		packets: make(chan sacn.DataPacket, packetBufferSize),
+		stop:    make(chan struct{}),
+		clients: make(map[net.Conn]bool),
+	}
+}
+
+// Listen starts accepting connections on the given address, e.g. ":5568". Any number of transmitters can
+// be connected at the same time. Only one address can be listened on.
+func (r *TCPReceiver) Listen(addr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return fmt.Errorf("the receiver is closed")
+	}
+	if r.listener != nil {
+		return fmt.Errorf("the receiver is already listening on %v", r.listener.Addr())
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	r.listener = l
+	r.wg.Add(1)
+	go r.accept(l)
+	return nil
+}
+
+// Addr returns the address the receiver listens on, or nil if it does not listen.
+func (r *TCPReceiver) Addr() net.Addr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.listener == nil {
+		return nil
+	}
+	return r.listener.Addr()
+}
+
+// Packets returns the channel on which all received packets of all transmitters are delivered. Packets
+// are never dropped: if the channel is not read, the transmitters are blocked by TCP flow control.
+// The channel gets closed when the receiver is closed.
+func (r *TCPReceiver) Packets() <-chan sacn.DataPacket {
+	return r.packets
+}
+
+// accept accepts new connections until the listener is closed
+func (r *TCPReceiver) accept(l net.Listener) {
+	defer r.wg.Done()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return //the listener was closed
+		}
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			conn.Close()
+			return
+		}
+		r.clients[conn] = true
+		r.wg.Add(1)
+		r.mu.Unlock()
+		go r.read(conn)
+	}
+}
+
+// read delivers all packets of the connection until it is closed. Frames that are no valid sACN data
+// packets are skipped, frames with an invalid length close the connection.
+func (r *TCPReceiver) read(conn net.Conn) {
+	defer r.wg.Done()
+	defer func() {
+		r.mu.Lock()
+		delete(r.clients, conn)
+		r.mu.Unlock()
+		conn.Close()
+	}()
+	var header [4]byte
+	buf := make([]byte, maxFrameLen)
+	for {
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return
+		}
+		length := binary.BigEndian.Uint32(header[:])
+		if length > maxFrameLen {
+			return //the stream is corrupted
+		}
+		if _, err := io.ReadFull(conn, buf[:length]); err != nil {
+			return
+		}
+		p, err := sacn.NewDataPacketRaw(buf[:length])
+		if err != nil {
+			continue
+		}
+		select {
+		case r.packets <- p:
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Close stops listening, closes all connections and the packet channel.
+func (r *TCPReceiver) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.stop)
+	var err error
+	if r.listener != nil {
+		err = r.listener.Close()
+	}
+	for conn := range r.clients {
+		conn.Close()
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	close(r.packets)
+	return err
+}
@@ -0,0 +1,99 @@
+package tcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+func newTestPacket(universe uint16, data []byte) sacn.DataPacket {
+	p := sacn.NewDataPacket()
+	p.SetCID([16]byte{1})
+	p.SetSourceName("tcp")
+	p.SetUniverse(universe)
+	p.SetData(data)
+	return p
+}
+
+func TestRoundTrip(t *testing.T) {
+	rx := NewTCPReceiver()
+	defer rx.Close()
+	if err := rx.Listen("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	//two transmitters at the same time
+	tx1, tx2 := NewTCPTransmitter(), NewTCPTransmitter()
+	defer tx1.Close()
+	defer tx2.Close()
+	for _, tx := range []*TCPTransmitter{tx1, tx2} {
+		if err := tx.Dial(rx.Addr().String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sent := make(map[uint16]sacn.DataPacket)
+	for i := uint16(1); i <= 10; i++ {
+		p := newTestPacket(i, make([]byte, 512))
+		p.Data()[0] = byte(i)
+		sent[i] = p
+		tx := tx1
+		if i%2 == 0 {
+			tx = tx2
+		}
+		if err := tx.Send(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range sent {
+		select {
+		case p := <-rx.Packets():
+			should := sent[p.Universe()]
+			if !p.Equal(should) {
+				t.Errorf("Wrong output! Was: %v; Should've been: %v", p, should)
+			}
+			delete(sent, p.Universe())
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Packets were not received: %v", len(sent))
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	rx := NewTCPReceiver()
+	if err := rx.Listen("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	addr := rx.Addr().String()
+	tx := NewTCPTransmitter()
+	defer tx.Close()
+	if err := tx.Send(newTestPacket(1, []byte{1})); err == nil {
+		t.Error("Err was nil! Should have been an error for a not connected transmitter!")
+	}
+	if err := tx.Dial(addr); err != nil {
+		t.Fatal(err)
+	}
+	rx.Close()
+	if _, ok := <-rx.Packets(); ok {
+		t.Error("The channel was not closed!")
+	}
+
+	rx = NewTCPReceiver()
+	defer rx.Close()
+	if err := rx.Listen(addr); err != nil {
+		t.Fatal(err)
+	}
+	//the transmitter notices the lost connection on writing and reconnects in the background
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		tx.Send(newTestPacket(1, []byte{1, 2}))
+		select {
+		case p := <-rx.Packets():
+			if p.Universe() != 1 {
+				t.Errorf("Wrong universe! Was: %v; Should've been: %v", p.Universe(), 1)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	t.Fatal("The transmitter did not reconnect!")
+}
@@ -0,0 +1,129 @@
+/*
+Package testutil provides helpers for testing code that uses the sacn package, without mocking its
+internals. A TestServer receives the packets that a Transmitter sends on a real UDP socket on localhost.
+*/
+package testutil
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+// DefaultTimeout is the time NextPacket waits for a packet, if no other timeout was set.
+const DefaultTimeout = 2 * time.Second
+
+// TestServer captures the packets a Transmitter sends on one universe. Use NewTestServer to create one
+// and defer its Close.
+type TestServer struct {
+	t        testing.TB
+	tx       *sacn.Transmitter
+	universe uint16
+	conn     *net.UDPConn
+	addr     net.UDPAddr
+	incoming chan sacn.DataPacket
+	done     chan struct{}
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	packets []sacn.DataPacket
+}
+
+// NewTestServer binds a UDP socket on a random port on localhost and adds it as unicast destination of
+// the universe on the transmitter. The existing destinations of the universe are kept.
+// If the server can not be created, the test fails immediately.
+func NewTestServer(t testing.TB, tx *sacn.Transmitter, universe uint16) *TestServer {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("testutil: could not open the socket: %v", err)
+	}
+	s := &TestServer{
+		t:        t,
+		tx:       tx,
+		universe: universe,
+		conn:     conn,
+		addr:     *conn.LocalAddr().(*net.UDPAddr),
+		incoming: make(chan sacn.DataPacket, 1024),
+		done:     make(chan struct{}),
+		timeout:  DefaultTimeout,
+	}
+	if err := tx.AddRawDestination(universe, s.addr); err != nil {
+		conn.Close()
+		t.Fatalf("testutil: could not add the destination: %v", err)
+	}
+	go s.receive()
+	return s
+}
+
+// receive reads all packets until the socket is closed
+func (s *TestServer) receive() {
+	defer close(s.done)
+	defer close(s.incoming)
+	buf := make([]byte, 1144)
+	for {
+		n, err := s.conn.Read(buf)
+		if err != nil {
+			return //the socket was closed
+		}
+		p, err := sacn.NewDataPacketRaw(buf[:n])
+		if err != nil || p.Universe() != s.universe {
+			continue
+		}
+		s.mu.Lock()
+		s.packets = append(s.packets, p)
+		s.mu.Unlock()
+		select {
+		case s.incoming <- p:
+		default: //NextPacket is not used, so the packets are only stored
+		}
+	}
+}
+
+// Addr returns the address the server receives on.
+func (s *TestServer) Addr() net.UDPAddr {
+	return s.addr
+}
+
+// SetTimeout sets the time NextPacket waits for a packet. The default is DefaultTimeout.
+func (s *TestServer) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// NextPacket returns the next packet that was received. The packets are returned in the order they were
+// received. If no packet arrives within the timeout, an error is returned.
+func (s *TestServer) NextPacket() (sacn.DataPacket, error) {
+	select {
+	case p, ok := <-s.incoming:
+		if !ok {
+			return sacn.DataPacket{}, fmt.Errorf("the test server is closed")
+		}
+		return p, nil
+	case <-time.After(s.timeout):
+		return sacn.DataPacket{}, fmt.Errorf("no packet was received on universe %v within %v", s.universe, s.timeout)
+	}
+}
+
+// Packets returns all packets that were received so far.
+func (s *TestServer) Packets() []sacn.DataPacket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sacn.DataPacket(nil), s.packets...)
+}
+
+// Close removes the server from the destinations of the universe and closes its socket.
+func (s *TestServer) Close() {
+	dests := make([]net.UDPAddr, 0)
+	for _, dest := range s.tx.Destinations(s.universe) {
+		if !dest.IP.Equal(s.addr.IP) || dest.Port != s.addr.Port {
+			dests = append(dests, dest)
+		}
+	}
+	s.tx.SetRawDestinations(s.universe, dests)
+	s.conn.Close()
+	<-s.done
+}
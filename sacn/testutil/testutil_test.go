@@ -0,0 +1,71 @@
+package testutil
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Hundemeier/go-sacn/sacn"
+)
+
+func TestTestServer(t *testing.T) {
+	tx, err := sacn.NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewTestServer(t, &tx, 5)
+	ch, err := tx.Activate(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+
+	first, err := server.NextPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Universe() != 5 || first.CID() != [16]byte{1} {
+		t.Errorf("Wrong packet! Universe: %v; CID: %v", first.Universe(), first.CID())
+	}
+	ch <- []byte{1, 2, 3, 4}
+	for {
+		p, err := server.NextPacket()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(p.Data(), []byte{1, 2, 3, 4}) {
+			if p.Sequence() <= first.Sequence() {
+				t.Errorf("Wrong sequence! Was: %v; Should've been greater than: %v", p.Sequence(), first.Sequence())
+			}
+			break
+		}
+	}
+	if n := len(server.Packets()); n < 2 {
+		t.Errorf("Wrong number of packets! Was: %v; Should've been at least: %v", n, 2)
+	}
+
+	server.Close()
+	if dests := tx.Destinations(5); len(dests) != 0 {
+		t.Errorf("The server was not removed from the destinations: %v", dests)
+	}
+	if _, err := server.NextPacket(); err == nil {
+		t.Error("NextPacket on a closed server did not return an error!")
+	}
+}
+
+func TestTestServerTimeout(t *testing.T) {
+	tx, err := sacn.NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewTestServer(t, &tx, 5)
+	defer server.Close()
+	server.SetTimeout(50 * time.Millisecond)
+	start := time.Now()
+	if _, err := server.NextPacket(); err == nil {
+		t.Error("NextPacket did not time out!")
+	}
+	if took := time.Since(start); took < 50*time.Millisecond {
+		t.Errorf("NextPacket returned after %v, before the timeout", took)
+	}
+}
@@ -0,0 +1,42 @@
+package sacn
+
+import "bytes"
+
+// TiebreakerMode determines which source wins on a universe, if multiple sources send with the same
+// priority, see ReceiverSocket.SetTiebreakerMode. E1.31 leaves this to the implementation.
+type TiebreakerMode int
+
+const (
+	// TiebreakerCIDLexicographic selects the source with the lowest CID, compared byte by byte. This is
+	// deterministic, also across restarts of the sources. This is the default.
+	TiebreakerCIDLexicographic TiebreakerMode = iota
+	// TiebreakerLatestReceived selects the source of the latest packet, so the output switches between
+	// the sources with every packet.
+	TiebreakerLatestReceived
+	// TiebreakerFirstSeen keeps the source that was selected first, until it terminates or times out.
+	TiebreakerFirstSeen
+)
+
+// SetTiebreakerMode sets how the receiver selects the source of a universe, if multiple sources send with
+// the same priority. The default is TiebreakerCIDLexicographic. This applies to the OnChangeCallback and
+// the listeners without a Merger.
+func (r *ReceiverSocket) SetTiebreakerMode(mode TiebreakerMode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tiebreaker = mode
+}
+
+// winsTie returns true if the challenger replaces the current source, that sends with the same priority
+func (r *ReceiverSocket) winsTie(current, challenger DataPacket) bool {
+	r.mu.Lock()
+	mode := r.tiebreaker
+	r.mu.Unlock()
+	switch mode {
+	case TiebreakerLatestReceived:
+		return true
+	case TiebreakerFirstSeen:
+		return false
+	}
+	currentCID, challengerCID := current.CID(), challenger.CID()
+	return bytes.Compare(challengerCID[:], currentCID[:]) < 0
+}
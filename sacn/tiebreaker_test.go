@@ -0,0 +1,36 @@
+package sacn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetTiebreakerMode(t *testing.T) {
+	tests := []struct {
+		mode   TiebreakerMode
+		should []byte //the first CID byte of the delivered packets
+	}{
+		{TiebreakerCIDLexicographic, []byte{2, 1}},
+		{TiebreakerLatestReceived, []byte{2, 1, 2}},
+		{TiebreakerFirstSeen, []byte{2, 2}},
+	}
+	for _, tt := range tests {
+		r := newReceiverSocket()
+		r.SetTiebreakerMode(tt.mode)
+		packets, _ := r.ListenUniverse(1)
+		r.handle(newTestPacket(t, 1, [16]byte{2}, 100, []byte{1, 0}))
+		r.handle(newTestPacket(t, 1, [16]byte{1}, 100, []byte{2, 0}))
+		next := newTestPacket(t, 1, [16]byte{2}, 100, []byte{3, 0})
+		next.SetSequence(1)
+		r.handle(next)
+
+		var delivered []byte
+		for len(packets) > 0 {
+			p := <-packets
+			delivered = append(delivered, p.CID()[0])
+		}
+		if !reflect.DeepEqual(delivered, tt.should) {
+			t.Errorf("Mode %v: Wrong output! Was: %v; Should've been: %v", tt.mode, delivered, tt.should)
+		}
+	}
+}
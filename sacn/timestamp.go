@@ -0,0 +1,73 @@
+package sacn
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// TimestampStartCode is the user defined start code of the packets that carry the send time, see
+// Transmitter.SetTimestampMode.
+const TimestampStartCode = 0xF0
+
+// TimestampMode determines if a Transmitter sends timestamp packets, see Transmitter.SetTimestampMode.
+type TimestampMode int
+
+const (
+	// TimestampDisabled sends no timestamp packets. This is the default.
+	TimestampDisabled TimestampMode = iota
+	// TimestampInStartCode0xF0 sends a packet with the start code 0xF0 after every DMX packet. Its slots 1-4
+	// contain the lower 32 bits of the unix time in nanoseconds of the moment the packet was sent.
+	TimestampInStartCode0xF0
+)
+
+// SetTimestampMode sets if timestamp packets are sent on all universes. E1.31 defines no timestamp field,
+// so the send time is transmitted in an additional packet with the user defined start code 0xF0, that
+// shares the sequence numbers with the DMX packets. A ReceiverSocket can compute the one-way delay from
+// these packets, see ReceiverSocket.SetOnTimestamp. Receivers that only handle the DMX start code 0x00
+// ignore them. The delay is only meaningful, if the clocks of both machines are synchronized.
+func (t *Transmitter) SetTimestampMode(mode TimestampMode) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timestampMode = mode
+}
+
+// timestampPacket returns a copy of the packet with the timestamp start code and the given time as data
+func timestampPacket(p *DataPacket, at time.Time) *DataPacket {
+	ts := p.copy()
+	ts.SetDmxStartCode(TimestampStartCode)
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], uint32(at.UnixNano()))
+	ts.SetData(data[:])
+	return &ts
+}
+
+// timestampDelay returns the time between the send time stored in the timestamp packet and now.
+// As only 32 bits of the time are transmitted, the delay wraps around after about 4.29s.
+func timestampDelay(p DataPacket, now time.Time) (time.Duration, bool) {
+	data := p.Data()
+	if p.DmxStartCode() != TimestampStartCode || len(data) < 4 {
+		return 0, false
+	}
+	sent := binary.BigEndian.Uint32(data[:4])
+	return time.Duration(uint32(now.UnixNano()) - sent), true
+}
+
+// SetOnTimestamp sets a callback that gets called for every timestamp packet of a Transmitter that uses
+// TimestampInStartCode0xF0 with the one-way delay of the packet. Packets with the start code 0xF0 are
+// never delivered as DMX data, regardless if a callback is set. Gets called in own goroutine. Use nil to
+// remove the callback.
+func (r *ReceiverSocket) SetOnTimestamp(fn func(universe uint16, delay time.Duration)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onTimestamp = fn
+}
+
+// handleTimestamp invokes the timestamp callback with the delay of the packet
+func (r *ReceiverSocket) handleTimestamp(p DataPacket) {
+	delay, ok := timestampDelay(p, r.now())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ok && r.onTimestamp != nil {
+		go r.onTimestamp(p.Universe(), delay)
+	}
+}
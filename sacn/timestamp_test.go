@@ -0,0 +1,87 @@
+package sacn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampDelay(t *testing.T) {
+	sent := time.Unix(100, 999999000)
+	p := NewDataPacket()
+	ts := timestampPacket(&p, sent)
+	if ts.DmxStartCode() != TimestampStartCode || len(ts.Data()) != 4 {
+		t.Fatalf("Wrong timestamp packet! Start code: %#x; Data: %v", ts.DmxStartCode(), ts.Data())
+	}
+	delay, ok := timestampDelay(*ts, sent.Add(3*time.Millisecond))
+	if !ok || delay != 3*time.Millisecond {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", delay, 3*time.Millisecond)
+	}
+	//the lower 32 bits of the time wrap around every 4.29s
+	sent = time.Unix(0, 1<<32-int64(time.Millisecond))
+	ts = timestampPacket(&p, sent)
+	if delay, _ := timestampDelay(*ts, sent.Add(2*time.Millisecond)); delay != 2*time.Millisecond {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", delay, 2*time.Millisecond)
+	}
+	if _, ok := timestampDelay(p, sent); ok {
+		t.Error("A DMX packet was treated as timestamp packet!")
+	}
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetTimestampMode(TimestampInStartCode0xF0)
+	trans.SetMulticast(1, true)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	for i := 0; ; i++ {
+		if stats, _ := trans.Stats(1); stats.PacketsSent >= 2 {
+			break
+		}
+		if i > 100 {
+			t.Fatal("No timestamp packet was sent!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	r := newReceiverSocket()
+	dmx, err := r.ListenDMX(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delays := make(chan time.Duration, 1)
+	r.SetOnTimestamp(func(universe uint16, delay time.Duration) {
+		delays <- delay
+	})
+	sender.mu.Lock()
+	raw := [][]byte{sender.packets[0], sender.packets[1]}
+	sender.mu.Unlock()
+	for _, b := range raw {
+		r.handleRaw(b)
+	}
+	select {
+	case delay := <-delays:
+		if delay < 0 || delay > time.Second {
+			t.Errorf("Wrong delay! Was: %v; Should've been less than %v", delay, time.Second)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The timestamp callback was not called!")
+	}
+	//only the DMX packet is delivered as data
+	<-dmx
+	select {
+	case data := <-dmx:
+		t.Errorf("The timestamp packet was delivered as DMX data: %v", data)
+	default:
+	}
+}
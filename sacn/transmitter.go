@@ -1,119 +1,683 @@
 package sacn
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"log"
 	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/net/ipv4"
 )
 
 // Transmitter : This struct is for managing the transmitting of sACN data.
 // It handles all channels and over watches what universes are already used.
+// All methods can be called from multiple goroutines at once, e.g. from a web UI and a playback engine.
 type Transmitter struct {
+	mu        *sync.RWMutex //protects the maps of the activated universes, the per-universe settings and the global settings that can be changed
 	universes map[uint16]chan []byte
 	//master stores the master DataPacket for all universes. Its the last send out packet
 	master            map[uint16]*DataPacket
-	destinations      map[uint16][]net.UDPAddr //holds the info about the destinations unicast or multicast
-	multicast         map[uint16]bool          //stores if an universe should be send out as multicast
-	bind              string                   //stores the string with the binding information
-	cid               [16]byte                 //the global cid for all packets
-	sourceName        string                   //the global source name for all packets
-	keepAliveInterval time.Duration            //the minium interval a packet is sent out higher can be used for
-	priority          byte                     //the priority at which our packets are sent out and receivers use to determine which packet to use.
+	stats             map[uint16]*universeStats  //holds the counters of all activated universes
+	paused            map[uint16]*int32          //1 if the output of the universe is paused. Accessed atomically
+	conns             map[uint16]*universeConn   //the connections of all activated universes
+	destMu            *sync.RWMutex              //protects destinations, destPriorities and multicast
+	packetMu          *sync.Mutex                //protects the content of the master packets. No other lock is taken while holding it
+	done              map[uint16]chan struct{}   //closed when the universe was deactivated
+	syncUniverses     map[uint16]bool            //the universes that are used for synchronization packets
+	noZeroSend        map[uint16]bool            //the universes whose keep alive packets are skipped if all slots are 0
+	deadlines         map[uint16]*time.Timer     //the timers that deactivate the universes, see SetDeadline
+	initialSequences  map[uint16]byte            //the sequence numbers of the first packets, see SetInitialSequenceNumber
+	multicastIfis     map[uint16]*net.Interface  //the multicast interfaces that override the global one per universe
+	loops             map[uint16]*frameLoop      //the running loops, see ActivateLoop
+	jitterBudgets     map[uint16]time.Duration   //the universes whose keep alive packets follow a grid, see SetJitterBudget
+	stereoPairs       map[uint16]*stereoPair     //the synchronized pairs of universes, see ActivateStereo
+	sendBufferSizes   map[uint16]int             //the send buffer sizes of the sockets, see SetSendBufferSize
+	destinations      map[uint16][]net.UDPAddr   //holds the info about the destinations unicast or multicast
+	multicast         map[uint16]bool            //stores if an universe should be send out as multicast
+	sourceNames       map[uint16]string          //source names that override the global source name per universe
+	cids              map[uint16][16]byte        //cids that override the global cid per universe
+	destPriorities    map[uint16]map[string]byte //priorities that override the priority for single destinations
+	onDataChange      map[uint16]func(old, new []byte)
+	groups            map[uint16]*UniverseGroup //the groups the universes belong to
+	watchdogs         map[uint16]*watchdog
+	bind              string         //stores the string with the binding information
+	cid               [16]byte       //the global cid for all packets
+	sourceName        string         //the global source name for all packets
+	keepAliveInterval time.Duration  //the minium interval a packet is sent out higher can be used for
+	priority          byte           //the priority at which our packets are sent out and receivers use to determine which packet to use.
+	multicastTTL      int            //the time-to-live that is used for multicast packets
+	multicastIfi      *net.Interface //the interface that is used for sending multicast packets. nil for the OS default
+	validate          bool           //if true, every packet is validated before sending and violations are logged
+	maxFrameRate      float64        //the maximum number of frames per second that are sent per universe. 0 for no limit
+	port              int            //the port that is used for unicast destinations
+	errors            chan<- error   //if not nil, network errors are reported on this channel
+	listen            func(bind string) (PacketSender, error)
+	listenIGMP        func() (igmpConn, error) //opens the socket of ListenForReceivers
+	interceptor       func(p *DataPacket) bool //if not nil, called for every packet before sending. false suppresses the packet
+	timestampMode     TimestampMode            //if timestamp packets are sent after every packet
+	rotation          *cidRotation             //not nil, if the CID is rotated, see WithCIDRotation
+	universe0         bool                     //if true, universe 0 can be activated, see SetUniverse0Broadcast
+	rtp               bool                     //if true, the packets are encapsulated in RTP, see WithRTPTransport
+	rtpPayloadType    byte
+	limitPriority     bool //if true, no packet is sent with a priority above maxPriority, see WithPriorityLimiter
+	maxPriority       byte
+	onJitterViolation func(universe uint16, actual time.Duration)
+	shutdown          *sync.Once      //makes AnnounceShutdown idempotent
+	closed            *int32          //1 after Close was called, accessed atomically under the lock
+	routines          *sync.WaitGroup //the goroutines of the activated universes, waited for by Close
+	syncConns         []*universeConn //the sockets of ActivateSync, closed by Close
+}
+
+// TransmitterOption is used to configure a Transmitter on creation via NewTransmitter.
+type TransmitterOption func(t *Transmitter)
+
+// WithMulticastTTL sets the time-to-live for multicast packets. The default is 1, so the packets do not
+// leave the local subnet. If sACN has to be routed between different subnets, a higher value has to be used.
+func WithMulticastTTL(ttl int) TransmitterOption {
+	return func(t *Transmitter) {
+		t.multicastTTL = ttl
+	}
+}
+
+// WithMulticastInterface sets the network interface that is used for sending out multicast packets.
+// If nil is given, the operating system chooses the interface.
+func WithMulticastInterface(ifi *net.Interface) TransmitterOption {
+	return func(t *Transmitter) {
+		t.multicastIfi = ifi
+	}
+}
+
+// WithBindAddress overrides the bind address that was given to NewTransmitter.
+// This is mostly useful for Transmitter.Fork, to bind the fork to a different network interface.
+func WithBindAddress(bind string) TransmitterOption {
+	return func(t *Transmitter) {
+		t.bind = bind
+	}
 }
 
 // NewTransmitter creates a new Transmitter object and returns it. Only use one object for one
 // network interface. bind is a string like "192.168.2.34" or "". It is used for binding the udp connection.
-// In most cases an empty string will be sufficient. The caller is responsible for closing!
+// In most cases an empty string will be sufficient. The caller is responsible for closing, see Close!
 // If you want to use multicast, you have to provide a binding string on some operation systems (eg Windows).
-func NewTransmitter(binding string, cid [16]byte, sourceName string) (Transmitter, error) {
+// Additional options like WithMulticastTTL can be provided.
+func NewTransmitter(binding string, cid [16]byte, sourceName string, opts ...TransmitterOption) (Transmitter, error) {
+	return NewTransmitterWithContext(context.Background(), binding, cid, sourceName, opts...)
+}
+
+// NewTransmitterWithContext works like NewTransmitter, but the test of the bind address is aborted when
+// the context is done. Then the error of the context is returned, e.g. context.DeadlineExceeded.
+// This is useful on systems where binding can block for a long time.
+func NewTransmitterWithContext(ctx context.Context, binding string, cid [16]byte, sourceName string, opts ...TransmitterOption) (Transmitter, error) {
 	//create transmitter:
 	tx := Transmitter{
+		mu:                &sync.RWMutex{},
 		universes:         make(map[uint16]chan []byte),
 		master:            make(map[uint16]*DataPacket),
+		stats:             make(map[uint16]*universeStats),
+		paused:            make(map[uint16]*int32),
+		conns:             make(map[uint16]*universeConn),
+		destMu:            &sync.RWMutex{},
+		packetMu:          &sync.Mutex{},
+		done:              make(map[uint16]chan struct{}),
+		syncUniverses:     make(map[uint16]bool),
+		noZeroSend:        make(map[uint16]bool),
+		deadlines:         make(map[uint16]*time.Timer),
+		initialSequences:  make(map[uint16]byte),
+		multicastIfis:     make(map[uint16]*net.Interface),
+		loops:             make(map[uint16]*frameLoop),
+		jitterBudgets:     make(map[uint16]time.Duration),
+		stereoPairs:       make(map[uint16]*stereoPair),
+		sendBufferSizes:   make(map[uint16]int),
+		shutdown:          &sync.Once{},
+		closed:            new(int32),
+		routines:          &sync.WaitGroup{},
 		destinations:      make(map[uint16][]net.UDPAddr),
 		multicast:         make(map[uint16]bool),
-		bind:              "",
+		sourceNames:       make(map[uint16]string),
+		cids:              make(map[uint16][16]byte),
+		destPriorities:    make(map[uint16]map[string]byte),
+		onDataChange:      make(map[uint16]func(old, new []byte)),
+		groups:            make(map[uint16]*UniverseGroup),
+		watchdogs:         make(map[uint16]*watchdog),
+		bind:              binding,
 		cid:               cid,
 		sourceName:        sourceName,
-		keepAliveInterval: time.Second * 1,
+		keepAliveInterval: keepAliveMax,
+		multicastTTL:      1,
+		port:              5568,
 	}
-	//create a udp address for testing, if the given bind address is possible
-	addr, err := net.ResolveUDPAddr("udp", binding)
-	if err != nil {
-		return tx, err
+	tx.listen = tx.listenUDP
+	tx.listenIGMP = listenIGMP
+	for _, opt := range opts {
+		opt(&tx)
 	}
-	serv, err := net.ListenUDP("udp", addr)
-	serv.Close()
-	if err != nil {
+	if tx.multicastTTL < 0 || tx.multicastTTL > 255 {
+		return tx, fmt.Errorf("the multicast ttl was %v and therefore is not in range [0-255]", tx.multicastTTL)
+	}
+	if tx.maxFrameRate < 0 {
+		return tx, fmt.Errorf("the maximum frame rate was %v and must not be negative", tx.maxFrameRate)
+	}
+	if tx.limitPriority && tx.maxPriority > 200 {
+		return tx, fmt.Errorf("the priority limit was %v and therefore is not in range [0-200]", tx.maxPriority)
+	}
+	if tx.rtp && tx.rtpPayloadType > rtpMaxPayload {
+		return tx, fmt.Errorf("the RTP payload type was %v and therefore is not in range [0-%v]", tx.rtpPayloadType, rtpMaxPayload)
+	}
+	if tx.rotation != nil && tx.rotation.interval <= 0 {
+		return tx, fmt.Errorf("the CID rotation interval was %v and has to be greater than 0", tx.rotation.interval)
+	}
+	//create a udp connection for testing, if the given bind address is possible
+	if err := testBind(ctx, tx.bind); err != nil {
 		return tx, err
 	}
-	//if everything is ok, set the bind address string
-	tx.bind = binding
+	if tx.rotation != nil {
+		tx.rotation.cid = tx.cid
+		go tx.rotateCIDs()
+	}
 	return tx, nil
 }
 
+// testBind opens and closes a udp connection on the bind address. It returns early with the error of the
+// context, if the context is done before.
+func testBind(ctx context.Context, bind string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	result := make(chan error, 1)
+	go func() {
+		var lc net.ListenConfig
+		serv, err := lc.ListenPacket(ctx, "udp", bind)
+		if err == nil {
+			serv.Close()
+		}
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Activate starts sending out DMX data on the given universe. It returns a channel that accepts
 // byte slices and transmits them to the unicast or multicast destination.
-// If you want to deactivate the universe, simply close the channel.
+// The universe has to be in range [1-63999]. Frames longer than 512 bytes are dropped and an error is
+// reported on the error channel (see WithErrorChannel).
+// If you want to deactivate the universe, simply close the channel. Then three packets with the
+// stream terminated flag are sent out.
+// Activate is safe for concurrent use.
 func (t *Transmitter) Activate(universe uint16) (chan<- []byte, error) {
+	return t.activate(universe, make([]byte, 512), 0) //set 0 data
+}
+
+// ActivateMany activates all given universes at once, so no other activation can happen in between.
+// It returns the channels of the universes mapped by their universe number. If one of the universes
+// could not be activated, the already activated ones are removed again without sending any packet and
+// the error is returned.
+func (t *Transmitter) ActivateMany(universes []uint16) (map[uint16]chan<- []byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	channels := make(map[uint16]chan<- []byte, len(universes))
+	for _, universe := range universes {
+		ch, err := t.activateLocked(universe, make([]byte, 512), 0)
+		if err != nil {
+			//the goroutines of the universes can not send anything while we hold the lock,
+			//so the universes are removed without sending packets with the stream terminated flag
+			for univ := range channels {
+				conn := t.conns[univ]
+				close(t.universes[univ])
+				t.removeUniverseLocked(univ)
+				conn.forceClose()
+			}
+			return nil, err
+		}
+		channels[universe] = ch
+	}
+	return channels, nil
+}
+
+// ActivateWithData works like Activate, but uses the given data as the first DMX frame for the
+// universe. So the first packet that is sent out already contains this data and not zeros.
+// The data has to be 1 to 512 bytes long.
+func (t *Transmitter) ActivateWithData(universe uint16, initialData []byte) (chan<- []byte, error) {
+	if len(initialData) < 1 || len(initialData) > 512 {
+		return nil, fmt.Errorf("the initial data length was %v and therefore is not in range [1-512]", len(initialData))
+	}
+	return t.activate(universe, initialData, 0)
+}
+
+// ActivateBuffered works like Activate, but the returned channel buffers bufSize frames. So writing
+// to the channel does not block, if sending out the packets is slow. If the buffer is full, the oldest
+// frame is dropped. Dropped frames are counted in the stats of the universe.
+func (t *Transmitter) ActivateBuffered(universe uint16, bufSize int) (chan<- []byte, error) {
+	if bufSize < 1 {
+		return nil, fmt.Errorf("the buffer size was %v and has to be at least 1", bufSize)
+	}
+	return t.activate(universe, make([]byte, 512), bufSize)
+}
+
+func (t *Transmitter) activate(universe uint16, initialData []byte, bufSize int) (chan<- []byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.activateLocked(universe, initialData, bufSize)
+}
+
+// activateLocked activates the universe. The caller has to hold the lock.
+func (t *Transmitter) activateLocked(universe uint16, initialData []byte, bufSize int) (chan<- []byte, error) {
+	if !IsValidDataUniverse(universe) && !(universe == 0 && t.universe0) {
+		return nil, fmt.Errorf("the universe was %v and therefore is not in range [1-63999]", universe)
+	}
+	if atomic.LoadInt32(t.closed) != 0 {
+		return nil, ErrTransmitterClosed
+	}
 	//check if the universe is already activated
-	if t.IsActivated(universe) {
+	if _, ok := t.universes[universe]; ok {
 		return nil, fmt.Errorf("the given universe %v is already activated", universe)
 	}
-	//create udp socket
-	ServerAddr, err := net.ResolveUDPAddr("udp", t.bind)
-	if err != nil {
-		return nil, err
+	if t.syncUniverses[universe] {
+		return nil, fmt.Errorf("the given universe %v is used for synchronization", universe)
 	}
-	serv, err := net.ListenUDP("udp", ServerAddr)
+	//create udp socket
+	conn, err := t.openConn(t.multicastIfis[universe])
 	if err != nil {
 		return nil, err
 	}
+	serv := newUniverseConn(conn)
 
-	ch := make(chan []byte)
+	ch := make(chan []byte, bufSize)
 	t.universes[universe] = ch
 	//init master packet
 	masterPacket := NewDataPacket()
-	masterPacket.SetCID(t.cid)
-	masterPacket.SetSourceName(t.sourceName)
+	if cid, ok := t.cids[universe]; ok {
+		masterPacket.SetCID(cid)
+	} else {
+		masterPacket.SetCID(t.currentCID())
+	}
+	if name, ok := t.sourceNames[universe]; ok {
+		masterPacket.SetSourceName(name)
+	} else {
+		masterPacket.SetSourceName(t.sourceName)
+	}
 	masterPacket.SetUniverse(universe)
-	masterPacket.SetData(make([]byte, 512)) //set 0 data
+	masterPacket.SetData(initialData)
+	if seq, ok := t.initialSequences[universe]; ok {
+		masterPacket.SetSequence(seq - 1) //the sequence number is incremented before every packet
+	}
 	if t.priority > 0x0 {
 		masterPacket.SetPriority(t.priority)
 	}
-	t.master[universe] = &masterPacket
+	master := &masterPacket
+	t.master[universe] = master
+	stats := &universeStats{}
+	t.stats[universe] = stats
+	paused := new(int32)
+	t.paused[universe] = paused
+	t.conns[universe] = serv
+	done := make(chan struct{})
+	t.done[universe] = done
+	if w, ok := t.watchdogs[universe]; ok {
+		w.touch()
+		go t.runWatchdog(universe, w, serv)
+	}
 
 	//make goroutine that sends out every second a "keep alive" packet
+	t.routines.Add(2)
 	go func() {
+		defer t.routines.Done()
+		defer t.recoverUniverse(universe, serv, nil)
+		var timer keepAliveTimer
+		defer timer.stop()
 		for {
-			//if we have no master packet,break the loop
-			if _, ok := t.master[universe]; !ok {
+			//if the universe was deactivated, break the loop
+			if !t.isCurrentConn(universe, serv) {
 				break
 			}
-			t.sendOut(serv, universe)
-			time.Sleep(t.keepAliveInterval)
+			if atomic.LoadInt32(paused) == 0 && !t.skipZeroSend(universe, master) {
+				t.sendOut(serv, universe)
+			}
+			t.waitKeepAlive(universe, &timer, done)
 		}
 	}()
 
 	go func() {
-		for i := range ch {
-			t.master[universe].SetData(i[:])
+		defer t.routines.Done()
+		defer close(done)
+		defer t.recoverUniverse(universe, serv, ch)
+		frames := (<-chan []byte)(ch)
+		if bufSize > 0 {
+			frames = bufferFrames(ch, bufSize, stats, serv.stop)
+		}
+		t.receiveFrames(frames, serv.stop, func(data []byte) {
+			t.packetMu.Lock()
+			old := append([]byte(nil), master.Data()...)
+			err := master.SetData(data[:])
+			new := append([]byte(nil), master.Data()...)
+			t.packetMu.Unlock()
+			if err != nil {
+				t.reportError(fmt.Errorf("universe %v: %v", universe, err))
+				return //the frame is dropped
+			}
+			t.mu.RLock()
+			w, ok := t.watchdogs[universe]
+			callback := t.onDataChange[universe]
+			t.mu.RUnlock()
+			if ok {
+				w.touch()
+			}
+			if callback != nil && !bytes.Equal(old, new) {
+				callback(old, new)
+			}
+			if atomic.LoadInt32(paused) == 0 {
+				t.sendOut(serv, universe)
+			}
+		})
+		select {
+		case <-serv.stop:
+			//the universe was deactivated by the transmitter, the channel is still owned by the caller
+			go func() {
+				for range ch {
+				}
+			}()
+		default:
+		}
+		//the universe may have been removed forcibly, see CloseWithTimeout
+		if !t.isCurrentConn(universe, serv) {
+			return
+		}
+		//if the channel was closed we send three packets with stream terminated bit set (E1.31 6.7.1)
+		t.packetMu.Lock()
+		master.SetStreamTerminated(true)
+		t.packetMu.Unlock()
+		for i := 0; i < 3; i++ {
 			t.sendOut(serv, universe)
 		}
-		//if the channel was closed we send a last packet with stream terminated bit set
-		t.master[universe].SetStreamTerminated(true)
-		t.sendOut(serv, universe)
 		//if the channel was closed, we deactivate the universe
-		delete(t.master, universe)
-		delete(t.universes, universe)
-		serv.Close()
+		if t.removeUniverse(universe, serv) {
+			serv.close()
+		}
 	}()
 
 	return ch, nil
 }
 
+// recoverUniverse recovers from a panic in one of the goroutines of the universe, e.g. caused by a
+// PacketSender whose socket was closed externally. The universe is removed without sending the packets
+// with the stream terminated flag, so it can be activated again. If ch is given, it is drained until it
+// gets closed, so writing to it does not block. Has to be called deferred.
+func (t *Transmitter) recoverUniverse(universe uint16, conn *universeConn, ch <-chan []byte) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Printf("sacn: recovered from panic on universe %v: %v", universe, r)
+	t.reportError(fmt.Errorf("universe %v was deactivated after a panic: %v", universe, r))
+	if t.removeUniverse(universe, conn) {
+		conn.forceClose()
+	}
+	if ch != nil {
+		go func() {
+			for range ch {
+			}
+		}()
+	}
+}
+
+// isCurrentConn returns true if the universe is activated with the given connection. The connection
+// identifies one activation of the universe.
+func (t *Transmitter) isCurrentConn(universe uint16, conn *universeConn) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.conns[universe] == conn
+}
+
+// removeUniverse deletes all state of the activated universe, if it is still activated with the given
+// connection. Returns false if the universe was already removed.
+func (t *Transmitter) removeUniverse(universe uint16, conn *universeConn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns[universe] != conn {
+		return false
+	}
+	t.removeUniverseLocked(universe)
+	return true
+}
+
+// removeUniverseLocked deletes all state of the activated universe. The caller has to hold the lock.
+func (t *Transmitter) removeUniverseLocked(universe uint16) {
+	if timer, ok := t.deadlines[universe]; ok {
+		timer.Stop()
+		delete(t.deadlines, universe)
+	}
+	t.removeStereoPairLocked(universe)
+	delete(t.sendBufferSizes, universe)
+	delete(t.master, universe)
+	delete(t.universes, universe)
+	delete(t.stats, universe)
+	delete(t.paused, universe)
+	delete(t.conns, universe)
+	delete(t.done, universe)
+}
+
+// bufferFrames reads all frames from the channel as soon as they arrive and buffers up to bufSize
+// frames for the returned channel. If the buffer is full, the oldest frame is dropped.
+// The returned channel is closed after the input channel was closed and all buffered frames were read,
+// or when stop is closed.
+func bufferFrames(in <-chan []byte, bufSize int, stats *universeStats, stop <-chan struct{}) <-chan []byte {
+	out := make(chan []byte)
+	go func() {
+		queue := make([][]byte, 0, bufSize)
+		for {
+			var outCh chan []byte //nil, so the case is never selected, if there is nothing to send
+			var next []byte
+			if len(queue) > 0 {
+				outCh, next = out, queue[0]
+			}
+			select {
+			case data, ok := <-in:
+				if !ok {
+					for _, data := range queue {
+						select {
+						case out <- data:
+						case <-stop:
+						}
+					}
+					close(out)
+					return
+				}
+				if len(queue) == bufSize {
+					queue = queue[1:]
+					atomic.AddUint64(&stats.dropped, 1)
+				}
+				queue = append(queue, data)
+			case outCh <- next:
+				queue = queue[1:]
+			case <-stop:
+				close(out)
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// receiveFrames calls send for every frame that is read from the channel until the channel or stop is
+// closed. If a maximum frame rate is set, frames that arrive too fast are dropped and only the most
+// recent one is sent out when the frame rate allows it.
+func (t *Transmitter) receiveFrames(ch <-chan []byte, stop <-chan struct{}, send func(data []byte)) {
+	if t.maxFrameRate <= 0 {
+		for {
+			select {
+			case data, ok := <-ch:
+				if !ok {
+					return
+				}
+				send(data)
+			case <-stop:
+				return
+			}
+		}
+	}
+	interval := time.Duration(float64(time.Second) / t.maxFrameRate)
+	var last time.Time
+	var pending []byte //the most recent frame that could not be sent yet
+	timer := time.NewTimer(interval)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	for {
+		select {
+		case data, ok := <-ch:
+			if !ok {
+				timer.Stop()
+				if pending != nil {
+					send(pending)
+				}
+				return
+			}
+			if since := time.Since(last); since < interval {
+				if pending == nil {
+					timer.Reset(interval - since)
+				}
+				pending = data
+				continue
+			}
+			send(data)
+			last = time.Now()
+		case <-timer.C:
+			send(pending)
+			pending = nil
+			last = time.Now()
+		case <-stop:
+			timer.Stop()
+			if pending != nil {
+				send(pending)
+			}
+			return
+		}
+	}
+}
+
+// Pause stops sending out packets on the given universe, without sending a stream terminated packet.
+// Data that is written to the channel in the meantime is stored and sent out after Resume was called.
+func (t *Transmitter) Pause(universe uint16) error {
+	return t.setPaused(universe, 1)
+}
+
+// Resume continues sending out packets on a universe that was paused via Pause.
+func (t *Transmitter) Resume(universe uint16) error {
+	return t.setPaused(universe, 0)
+}
+
+// Deactivate deactivates the universe like closing its channel: the packets with the stream terminated
+// flag are sent and it is waited until the universe was removed. The channel is not closed, it still
+// belongs to the caller and frames that are written to it afterwards are discarded. The loop of
+// ActivateLoop is stopped. Universes that belong to a UniverseGroup have to be deactivated by closing
+// their group. An error is returned if the universe is not activated.
+func (t *Transmitter) Deactivate(universe uint16) error {
+	t.mu.Lock()
+	conn, ok := t.conns[universe]
+	if !ok {
+		t.mu.Unlock()
+		return fmt.Errorf("the given universe %v is not activated", universe)
+	}
+	if _, ok := t.groups[universe]; ok {
+		t.mu.Unlock()
+		return fmt.Errorf("the given universe %v belongs to a group", universe)
+	}
+	loop := t.loops[universe]
+	delete(t.loops, universe)
+	done := t.done[universe]
+	t.mu.Unlock()
+	if loop != nil {
+		loop.halt()
+	}
+	conn.deactivate()
+	<-done
+	return nil
+}
+
+func (t *Transmitter) setPaused(universe uint16, value int32) error {
+	t.mu.RLock()
+	paused, ok := t.paused[universe]
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("the given universe %v is not activated", universe)
+	}
+	atomic.StoreInt32(paused, value)
+	return nil
+}
+
+// DisableZeroSend sets if the keep alive packets of the universe are skipped while all slots are 0.
+// This reduces the network load of installations with many universes that are mostly unused.
+// Data that is written to the channel is always sent, even if it is all 0.
+// Note that receivers time out after 2.5s without packets (E1.31 6.7.1) and may handle this like a
+// source loss instead of a blackout.
+func (t *Transmitter) DisableZeroSend(universe uint16, disable bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if disable {
+		t.noZeroSend[universe] = true
+	} else {
+		delete(t.noZeroSend, universe)
+	}
+}
+
+// skipZeroSend returns true if the keep alive packet of the universe has to be skipped, because all
+// slots are 0. See DisableZeroSend.
+func (t *Transmitter) skipZeroSend(universe uint16, packet *DataPacket) bool {
+	t.mu.RLock()
+	disabled := t.noZeroSend[universe]
+	t.mu.RUnlock()
+	if !disabled {
+		return false
+	}
+	t.packetMu.Lock()
+	defer t.packetMu.Unlock()
+	for _, value := range packet.Data() {
+		if value != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Channel returns the channel of the given universe, that was returned on activation.
+// The second return value is false, if the universe is not activated.
+func (t *Transmitter) Channel(universe uint16) (chan<- []byte, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	ch, ok := t.universes[universe]
+	return ch, ok
+}
+
+// SendNow sends the current data of the universe immediately from the calling goroutine, without
+// waiting for the next keep alive packet. This is useful after a network recovery or when a receiver
+// just came online. The packet gets the next sequence number, like any other packet of the universe.
+// An error is returned if the universe is not activated, is paused or the packet could not be sent.
+func (t *Transmitter) SendNow(universe uint16) error {
+	t.mu.RLock()
+	conn, ok := t.conns[universe]
+	paused := t.paused[universe]
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("the universe %v is not activated", universe)
+	}
+	if atomic.LoadInt32(paused) != 0 {
+		return fmt.Errorf("the universe %v is paused", universe)
+	}
+	return t.sendOut(conn, universe)
+}
+
 // IsActivated checks if the given universe was activated and returns true if this is the case
 func (t *Transmitter) IsActivated(universe uint16) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	if _, ok := t.universes[universe]; ok {
 		return true
 	}
@@ -122,6 +686,8 @@ func (t *Transmitter) IsActivated(universe uint16) bool {
 
 // GetActivated returns a slice with all activated universes
 func (t *Transmitter) GetActivated() (list []uint16) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	list = make([]uint16, 0)
 	for univ := range t.universes {
 		list = append(list, univ)
@@ -132,35 +698,48 @@ func (t *Transmitter) GetActivated() (list []uint16) {
 // SetMulticast is for setting wether or not a universe should be send out via multicast.
 // Keep in mind, that on some operating systems you have to provide a bind address.
 func (t *Transmitter) SetMulticast(universe uint16, multicast bool) {
+	t.destMu.Lock()
+	defer t.destMu.Unlock()
 	t.multicast[universe] = multicast
 }
 
 // IsMulticast returns wether or not multicast is turned on for the given universe. true: on
 func (t *Transmitter) IsMulticast(universe uint16) bool {
+	t.destMu.RLock()
+	defer t.destMu.RUnlock()
 	return t.multicast[universe]
 }
 
 // SetDestinations sets a slice of destinations for the universe that is used for sending out.
 // So multiple destinations are supported. Note: the existing slice will be overwritten!
 // If you want no unicasting, just set an empty slice. If there is a string that could not be
-// converted to an ip-address, this one is left out and an error slice will be returned,
-// but the indices of the errors are not the same as the string indices on which the errors happened.
+// converted to an ip-address, this one is left out and an error slice will be returned.
+// The errors are of type *DestinationError, which holds the index of the string that failed.
+// The port of the transmitter is used for all destinations (5568 if not set via WithPort).
 func (t *Transmitter) SetDestinations(universe uint16, destinations []string) []error {
+	return t.SetDestinationsWithPort(universe, destinations, t.port)
+}
+
+// SetDestinationsWithPort works like SetDestinations, but uses the given port for all destinations
+// instead of the port of the transmitter.
+func (t *Transmitter) SetDestinationsWithPort(universe uint16, destinations []string, port int) []error {
 	newDest := make([]net.UDPAddr, 0)
 	errs := make([]error, 0)
 
-	for _, dest := range destinations {
+	for i, dest := range destinations {
 		if dest == "" {
 			continue // continue if the string is empty
 		}
-		addr, err := net.ResolveUDPAddr("udp", dest+":5568")
+		addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(dest, strconv.Itoa(port)))
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, &DestinationError{Index: i, Destination: dest, Err: err})
 			continue
 		}
 		newDest = append(newDest, *addr)
 	}
-	t.destinations[universe] = newDest
+	t.destMu.Lock()
+	t.replaceDestinationsLocked(universe, newDest)
+	t.destMu.Unlock()
 
 	if len(errs) == 0 {
 		return nil
@@ -168,48 +747,413 @@ func (t *Transmitter) SetDestinations(universe uint16, destinations []string) []
 	return errs
 }
 
-// Destinations returns all destinations that have been set via SetDestinations. Note: the returned
+// SetRawDestinations sets the destinations for the universe like SetDestinations, but uses the given
+// addresses directly. The slice is copied, so later changes to it do not affect the transmitter.
+func (t *Transmitter) SetRawDestinations(universe uint16, dests []net.UDPAddr) {
+	newDest := make([]net.UDPAddr, len(dests))
+	copy(newDest, dests)
+	t.destMu.Lock()
+	t.replaceDestinationsLocked(universe, newDest)
+	t.destMu.Unlock()
+}
+
+// replaceDestinationsLocked sets the destinations of the universe and removes the priorities of the
+// destinations that are not set anymore. The caller has to hold destMu.
+func (t *Transmitter) replaceDestinationsLocked(universe uint16, dests []net.UDPAddr) {
+	t.destinations[universe] = dests
+	prios, ok := t.destPriorities[universe]
+	if !ok {
+		return
+	}
+	kept := make(map[string]bool, len(dests))
+	for _, dest := range dests {
+		kept[dest.String()] = true
+	}
+	for dest := range prios {
+		if !kept[dest] {
+			delete(prios, dest)
+		}
+	}
+	if len(prios) == 0 {
+		delete(t.destPriorities, universe)
+	}
+}
+
+// AddRawDestination appends the given address to the destinations of the universe.
+// An error is returned if the address has no ip or port, or if it is already a destination.
+func (t *Transmitter) AddRawDestination(universe uint16, dest net.UDPAddr) error {
+	if dest.IP == nil || dest.Port <= 0 {
+		return fmt.Errorf("the destination %v has no valid ip-address or port", dest.String())
+	}
+	t.destMu.Lock()
+	defer t.destMu.Unlock()
+	for _, existing := range t.destinations[universe] {
+		if existing.IP.Equal(dest.IP) && existing.Port == dest.Port {
+			return fmt.Errorf("the destination %v is already set for universe %v", dest.String(), universe)
+		}
+	}
+	//copy on write, so snapshots of the slice are never modified
+	newDest := make([]net.UDPAddr, len(t.destinations[universe]), len(t.destinations[universe])+1)
+	copy(newDest, t.destinations[universe])
+	t.destinations[universe] = append(newDest, dest)
+	return nil
+}
+
+// SetPerDestinationPriority sets the priority for the packets of the universe that are sent to the given
+// destination. So the same data can be sent with different priorities to different receivers, e.g. to
+// implement a failover on the receiver side. All other destinations and multicast use the priority of the
+// transmitter. The destination has to be set for the universe and the priority has to be in range [0-200].
+func (t *Transmitter) SetPerDestinationPriority(universe uint16, dest net.UDPAddr, prio byte) error {
+	if prio > 200 {
+		return fmt.Errorf("the priority was %v and therefore is not in range [0-200]", prio)
+	}
+	t.destMu.Lock()
+	defer t.destMu.Unlock()
+	found := false
+	for _, existing := range t.destinations[universe] {
+		if existing.String() == dest.String() {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("the destination %v is not set for universe %v", dest.String(), universe)
+	}
+	if _, ok := t.destPriorities[universe]; !ok {
+		t.destPriorities[universe] = make(map[string]byte)
+	}
+	t.destPriorities[universe][dest.String()] = prio
+	return nil
+}
+
+// DestinationError is returned by SetDestinations if a destination could not be resolved.
+type DestinationError struct {
+	Index       int    //the index of the destination in the given slice
+	Destination string //the destination that could not be resolved
+	Err         error
+}
+
+func (e *DestinationError) Error() string {
+	return fmt.Sprintf("destination %v (%q): %v", e.Index, e.Destination, e.Err)
+}
+
+// SetUniverseSourceName sets a source name for the given universe, that is used instead of the global
+// source name of the transmitter. This way every universe can appear as its own source.
+// The name must not be longer than 63 bytes. If the universe is already activated, the name is
+// used for all following packets.
+func (t *Transmitter) SetUniverseSourceName(universe uint16, name string) error {
+	if len(name) > 63 {
+		return fmt.Errorf("the source name was %v bytes long and therefore longer than 63 bytes", len(name))
+	}
+	t.mu.Lock()
+	t.sourceNames[universe] = name
+	t.mu.Unlock()
+	t.withMaster(universe, func(p *DataPacket) {
+		p.SetSourceName(name)
+	})
+	return nil
+}
+
+// SetUniverseCID sets a CID for the given universe, that is used instead of the global CID of the
+// transmitter. This way one transmitter can appear as multiple sACN sources.
+// The CID has to be set before the universe is activated.
+func (t *Transmitter) SetUniverseCID(universe uint16, cid [16]byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.universes[universe]; ok {
+		return fmt.Errorf("the universe %v is already activated", universe)
+	}
+	t.cids[universe] = cid
+	return nil
+}
+
+// SetInitialSequenceNumber sets the sequence number of the first packet that is sent after the universe
+// was activated, e.g. for compliance tests of the sequence wraparound. By default, the first packet has the
+// sequence number 1. The value is used for every following activation of the universe. It has to be set
+// before the universe is activated, otherwise an error is returned.
+func (t *Transmitter) SetInitialSequenceNumber(universe uint16, seq byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.universes[universe]; ok {
+		return fmt.Errorf("the universe %v is already activated", universe)
+	}
+	t.initialSequences[universe] = seq
+	return nil
+}
+
+// SetOnDataChange sets a callback for the universe that is called every time data is written to the
+// channel of the universe that differs from the data before. The callback gets copies of the data and is
+// called in the goroutine that sends out the data, so it should return quickly. Keep alive packets do not
+// invoke the callback. Use nil to remove the callback.
+func (t *Transmitter) SetOnDataChange(universe uint16, callback func(old, new []byte)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onDataChange[universe] = callback
+}
+
+// Destinations returns all destinations that have been set via SetDestinations, SetRawDestinations or
+// AddRawDestination. Note: the returned
 // slice contains deep copies and no change will affect the internal slice.
 func (t *Transmitter) Destinations(universe uint16) []net.UDPAddr {
+	t.destMu.RLock()
+	defer t.destMu.RUnlock()
 	new := make([]net.UDPAddr, len(t.destinations[universe]))
 	copy(new, t.destinations[universe])
 	return new
 }
 
 // handles sending and sequence numbering
-func (t *Transmitter) sendOut(server *net.UDPConn, universe uint16) {
-	//only send if the universe was activated
-	if _, ok := t.master[universe]; !ok {
-		return
+// If a packet could not be written, the connection is reopened in the background and the error is returned.
+func (t *Transmitter) sendOut(server *universeConn, universe uint16) error {
+	return t.sendOutContext(context.Background(), server, universe)
+}
+
+// sendOutContext works like sendOut, but the destinations that were not sent yet are skipped when the
+// context is done. Then the error of the context is returned.
+func (t *Transmitter) sendOutContext(ctx context.Context, server *universeConn, universe uint16) error {
+	//only send if the universe is still activated with this connection
+	t.mu.RLock()
+	packet, ok := t.master[universe]
+	stats := t.stats[universe]
+	current := t.conns[universe] == server
+	pair := t.stereoPairs[universe]
+	interceptor := t.interceptor
+	timestamps := t.timestampMode == TimestampInStartCode0xF0
+	t.mu.RUnlock()
+	if !ok || !current {
+		return nil
 	}
-	//increase sequence number
-	packet := t.master[universe]
+	server.sendMu.Lock()
+	defer server.sendMu.Unlock()
+	if atomic.LoadInt32(&server.reconnecting) != 0 {
+		return nil //the packet is skipped until the connection was replaced
+	}
+	//increase sequence number and send a copy, so the master packet can be changed in the meantime
+	t.packetMu.Lock()
 	packet.SequenceIncr()
-	//check if we have to transmit via multicast
-	if t.multicast[universe] {
-		server.WriteToUDP(packet.getBytes(), generateMulticast(universe))
+	snapshot := packet.copy()
+	t.packetMu.Unlock()
+	out := &snapshot
+	if t.validate {
+		for _, err := range out.Validate() {
+			log.Printf("sacn: invalid packet on universe %v: %v", universe, err)
+		}
+	}
+	if interceptor != nil && !interceptor(out) {
+		return nil //the packet was suppressed
+	}
+	var sendErr, ctxErr error
+	var rtpHeader []byte
+	send := func(addr *net.UDPAddr, out *DataPacket) {
+		if err := ctx.Err(); err != nil {
+			ctxErr = err
+			return
+		}
+		if t.limitPriority && out.Priority() > t.maxPriority {
+			limited := out.copy()
+			limited.SetPriority(t.maxPriority)
+			out = &limited
+		}
+		b := out.wireBytes()
+		if rtpHeader != nil {
+			b = append(append([]byte(nil), rtpHeader...), b...)
+		}
+		n, err := server.write(b, addr)
+		stats.countSend(n, err)
+		if err != nil {
+			sendErr = err
+		}
+	}
+	dests, prios := t.destinationSnapshot(universe)
+	multicast := t.IsMulticast(universe)
+	sendAll := func(out *DataPacket) {
+		if t.rtp {
+			//all copies of the packet are the same packet in the RTP stream
+			rtpHeader = server.rtpHeader(t.rtpPayloadType, out.CID(), universe)
+		}
+		//check if we have to transmit via multicast. Universe 0 is always sent to its multicast address
+		if multicast || universe == 0 {
+			send(generateMulticast(universe), out)
+		}
+		//for every destination, send out
+		for _, dest := range dests {
+			if prio, ok := prios[dest.String()]; ok {
+				withPrio := out.copy()
+				withPrio.SetPriority(prio)
+				send(&dest, &withPrio)
+				continue
+			}
+			send(&dest, out)
+		}
+	}
+	sendAll(out)
+	if timestamps {
+		t.packetMu.Lock()
+		packet.SequenceIncr()
+		timestamp := timestampPacket(packet, time.Now())
+		t.packetMu.Unlock()
+		sendAll(timestamp)
+	}
+	if pair != nil && sendErr == nil && ctxErr == nil {
+		t.sendStereoSync(server, pair, universe, out.CID())
+	}
+	if sendErr != nil {
+		t.reportError(sendErr)
+		t.startReconnect(server, universe)
+		return sendErr
 	}
-	//for every destination, send out
-	for _, dest := range t.destinations[universe] {
-		server.WriteToUDP(packet.getBytes(), &dest)
+	return ctxErr
+}
+
+// masterPacket returns the master packet of the activated universe
+func (t *Transmitter) masterPacket(universe uint16) (*DataPacket, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	packet, ok := t.master[universe]
+	return packet, ok
+}
+
+// withMaster calls fn with the master packet of the activated universe, while holding the lock of the
+// master packets. Returns false if the universe is not activated.
+func (t *Transmitter) withMaster(universe uint16, fn func(p *DataPacket)) bool {
+	packet, ok := t.masterPacket(universe)
+	if !ok {
+		return false
+	}
+	t.packetMu.Lock()
+	defer t.packetMu.Unlock()
+	fn(packet)
+	return true
+}
+
+// destinationSnapshot returns the destinations of the universe and their priorities. The slice is never
+// modified, because all changes replace the slice. The map is a copy.
+func (t *Transmitter) destinationSnapshot(universe uint16) ([]net.UDPAddr, map[string]byte) {
+	t.destMu.RLock()
+	defer t.destMu.RUnlock()
+	prios := make(map[string]byte, len(t.destPriorities[universe]))
+	for dest, prio := range t.destPriorities[universe] {
+		prios[dest] = prio
 	}
+	return t.destinations[universe], prios
 }
 
+// SetPacketInterceptor sets a function that is called with a copy of every packet right before it is
+// sent out. The function may modify the packet, the modified packet is sent instead. If the function
+// returns false, the packet is not sent at all. This is useful for tests and monitoring tools, e.g. to
+// simulate packet loss or to log the packets. The function is called in the goroutine that sends the
+// packets, so it should return quickly. Use nil to disable the interception.
+func (t *Transmitter) SetPacketInterceptor(fn func(p *DataPacket) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interceptor = fn
+}
+
+const (
+	keepAliveMin = 22 * time.Millisecond  //the time of one DMX frame at 44Hz
+	keepAliveMax = 800 * time.Millisecond //E1.31 6.6.1
+)
+
 // Allows the user to set a different interval than the internal default
-// of 1 second when the current data will be re-written to the network
-// to the outputs. (e.g. a much higher interval for less dynamically
+// of 800ms when the current data will be re-written to the network
+// to the outputs. (e.g. a higher interval for less dynamically
 // changing lighting and lower overall network traffic.)
-func (t *Transmitter) SetKeepAlive(interval time.Duration) {
+// E1.31 6.6.1 requires a packet at least every 800ms, so the interval has to be in range
+// [22ms-800ms]. Otherwise an error is returned and the interval is not changed.
+// Note that older versions used a default of 1s, which violated the specification.
+func (t *Transmitter) SetKeepAlive(interval time.Duration) error {
+	if interval < keepAliveMin || interval > keepAliveMax {
+		return fmt.Errorf("the keep alive interval was %v and therefore is not in range [%v-%v]", interval, keepAliveMin, keepAliveMax)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.keepAliveInterval = interval
+	return nil
 }
 
 // Allows the caller to set a priority on the sACN packets to be used in
 // situations when a destination receives data from multiple sources and
 // needs to decide which one to ignore.
+// If a limit was set via WithPriorityLimiter, higher priorities are reduced to the limit.
 func (t *Transmitter) SetPriority(prio byte) {
+	if t.limitPriority && prio > t.maxPriority {
+		prio = t.maxPriority
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.priority = prio
 }
 
+// WithPriorityLimiter sets the highest priority the transmitter sends packets with, e.g. to enforce a
+// network policy for guest controllers. Higher priorities are silently reduced to the limit, regardless if
+// they were set via SetPriority, SetPerDestinationPriority, a packet interceptor or are forwarded from
+// another source (see ReceiverSocket.Forward). The limit has to be in range [0-200].
+func WithPriorityLimiter(max byte) TransmitterOption {
+	return func(t *Transmitter) {
+		t.limitPriority = true
+		t.maxPriority = max
+	}
+}
+
+// SetUniverse0Broadcast allows to activate universe 0, that some non-compliant devices treat as
+// "send to all receivers": they accept its packets regardless of the universes they listen on.
+// The packets of universe 0 are always sent to its multicast address 239.255.0.0 and additionally to
+// all destinations of the universe. E1.31 reserves universe 0, so this violates the specification and
+// is only meant for interoperability with such devices. By default, activating universe 0 returns an error.
+func (t *Transmitter) SetUniverse0Broadcast(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.universe0 = enabled
+}
+
+// WithPacketValidation enables the validation of every packet before it is sent out. All violations of the
+// E1.31 specification are logged. This is meant for debugging, as it costs some performance.
+func WithPacketValidation() TransmitterOption {
+	return func(t *Transmitter) {
+		t.validate = true
+	}
+}
+
+// WithPort sets the port that is used for unicast destinations set via SetDestinations.
+// The default is the sACN port 5568.
+func WithPort(port int) TransmitterOption {
+	return func(t *Transmitter) {
+		t.port = port
+	}
+}
+
+// WithErrorChannel sets a channel on which network errors are reported. If a packet could not be sent,
+// the transmitter reopens the connection of the universe and retries with an exponential backoff.
+// Errors are dropped if the channel is not ready to receive.
+func WithErrorChannel(errs chan<- error) TransmitterOption {
+	return func(t *Transmitter) {
+		t.errors = errs
+	}
+}
+
+// WithMaxFrameRate limits the number of frames per second that are sent out on each universe.
+// Frames that arrive faster on the channel are dropped, only the most recent frame is sent out as soon
+// as the limit allows it. This is useful for receivers that can not handle high frame rates.
+// 0 means no limit, which is the default.
+func WithMaxFrameRate(fps float64) TransmitterOption {
+	return func(t *Transmitter) {
+		t.maxFrameRate = fps
+	}
+}
+
+// applyMulticastOptions sets the multicast socket options of the transmitter on the given connection
+func (t *Transmitter) applyMulticastOptions(conn *net.UDPConn) error {
+	p := ipv4.NewPacketConn(conn)
+	if err := p.SetMulticastTTL(t.multicastTTL); err != nil {
+		return err
+	}
+	if t.multicastIfi != nil {
+		if err := p.SetMulticastInterface(t.multicastIfi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func generateMulticast(universe uint16) *net.UDPAddr {
 	addr, _ := net.ResolveUDPAddr("udp", calcMulticastAddr(universe)+":5568")
 	return addr
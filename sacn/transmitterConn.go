@@ -0,0 +1,237 @@
+package sacn
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	reconnectMinBackoff = 50 * time.Millisecond
+	reconnectMaxBackoff = 5 * time.Second
+)
+
+// PacketSender is the part of a udp connection that is used for sending out packets.
+// *net.UDPConn implements this interface. Other implementations can be used via
+// Transmitter.SetPacketSenderFactory, e.g. for testing without real sockets.
+type PacketSender interface {
+	WriteToUDP(b []byte, addr *net.UDPAddr) (int, error)
+	Close() error
+}
+
+// universeConn holds the connection of one universe. The connection may be replaced on reconnection.
+type universeConn struct {
+	mu           sync.Mutex
+	conn         PacketSender
+	closed       bool          //true after close or forceClose, so reconnect does not replace the connection
+	sendMu       sync.Mutex    //serializes sendOut, so the sequence numbers are sent in order
+	rtp          rtpState      //protected by sendMu
+	reconnecting int32         //1 while reconnect is running, accessed atomically
+	stop         chan struct{} //closed to deactivate the universe without closing its channel
+	stopOnce     sync.Once
+}
+
+// newUniverseConn returns the state of a new activation with the given connection
+func newUniverseConn(conn PacketSender) *universeConn {
+	return &universeConn{conn: conn, stop: make(chan struct{})}
+}
+
+// deactivate signals the goroutines of the universe to deactivate it, as if its channel was closed.
+// It can be called multiple times.
+func (c *universeConn) deactivate() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// listenUDP opens a new udp connection on the given bind address and applies the multicast options
+func (t *Transmitter) listenUDP(bind string) (PacketSender, error) {
+	addr, err := net.ResolveUDPAddr("udp", bind)
+	if err != nil {
+		return nil, err
+	}
+	serv, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.applyMulticastOptions(serv); err != nil {
+		serv.Close()
+		return nil, err
+	}
+	return serv, nil
+}
+
+// SetPacketSenderFactory sets the function that is used to open the connection of a universe on
+// activation and on reconnection. It gets the bind address of the transmitter.
+// This is mainly meant for testing without real udp sockets. Use nil to restore the default, which
+// opens real udp connections. It has to be set before any universe is activated.
+func (t *Transmitter) SetPacketSenderFactory(fn func(bind string) (PacketSender, error)) {
+	if fn == nil {
+		fn = t.listenUDP
+	}
+	t.listen = fn
+}
+
+// multicastInterfaceSetter is implemented by PacketSenders that support setting the outgoing multicast
+// interface. *net.UDPConn is supported separately.
+type multicastInterfaceSetter interface {
+	SetMulticastInterface(ifi *net.Interface) error
+}
+
+// setMulticastInterface sets the outgoing multicast interface of the connection. nil restores the default
+// of the operating system. PacketSenders that do not support it are ignored.
+func setMulticastInterface(conn PacketSender, ifi *net.Interface) error {
+	switch c := conn.(type) {
+	case *net.UDPConn:
+		return ipv4.NewPacketConn(c).SetMulticastInterface(ifi)
+	case multicastInterfaceSetter:
+		return c.SetMulticastInterface(ifi)
+	}
+	return nil
+}
+
+// openConn opens the connection of a universe. If ifi is not nil, it is used as multicast interface
+// instead of the global one.
+func (t *Transmitter) openConn(ifi *net.Interface) (PacketSender, error) {
+	conn, err := t.listen(t.bind)
+	if err != nil || ifi == nil {
+		return conn, err
+	}
+	if err := setMulticastInterface(conn, ifi); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// SetPerUniverseMulticastInterface sets the network interface that is used for sending out the multicast
+// packets of the universe, e.g. to send some universes on the stage network and others on the house
+// network. It takes precedence over the interface of WithMulticastInterface and the bind address.
+// Unicast destinations are not affected. If the universe is already activated, the interface is applied
+// immediately. Use nil to use the global interface again.
+func (t *Transmitter) SetPerUniverseMulticastInterface(universe uint16, iface *net.Interface) error {
+	t.mu.Lock()
+	if iface == nil {
+		delete(t.multicastIfis, universe)
+	} else {
+		t.multicastIfis[universe] = iface
+	}
+	c, ok := t.conns[universe]
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if iface == nil {
+		iface = t.multicastIfi
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return setMulticastInterface(c.conn, iface)
+}
+
+// write sends the bytes to the given address over the current connection. The lock is not held during
+// the write, so a blocked write can be interrupted by forceClose.
+func (c *universeConn) write(b []byte, addr *net.UDPAddr) (int, error) {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	return conn.WriteToUDP(b, addr)
+}
+
+// close waits for a running sendOut and closes the current connection
+func (c *universeConn) close() error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.forceClose()
+}
+
+// forceClose closes the current connection without waiting for a running write, so a blocked write
+// is interrupted
+func (c *universeConn) forceClose() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return c.conn.Close()
+}
+
+// startReconnect starts reconnect in the background, if it is not already running. Until the connection
+// was replaced, sendOut skips the packets of the universe. After Close was called, nothing is started.
+func (t *Transmitter) startReconnect(c *universeConn, universe uint16) {
+	//the goroutine is added under the same lock that Close uses to set closed, so it is never added
+	//while Close waits for the goroutines
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if atomic.LoadInt32(t.closed) != 0 || !atomic.CompareAndSwapInt32(&c.reconnecting, 0, 1) {
+		return
+	}
+	t.routines.Add(1)
+	go func() {
+		defer t.routines.Done()
+		t.reconnect(c, universe)
+	}()
+}
+
+// reconnect closes the connection of the universe and opens a new one. Between the attempts it waits
+// with an exponential backoff. It stops retrying when the universe is deactivated, e.g. by Close.
+func (t *Transmitter) reconnect(c *universeConn, universe uint16) {
+	defer atomic.StoreInt32(&c.reconnecting, 0)
+	c.mu.Lock()
+	c.conn.Close()
+	c.mu.Unlock()
+	backoff := reconnectMinBackoff
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-c.stop:
+			return
+		}
+		if !t.isCurrentConn(universe, c) || atomic.LoadInt32(t.closed) != 0 {
+			return
+		}
+		t.mu.RLock()
+		ifi := t.multicastIfis[universe]
+		size, resize := t.sendBufferSizes[universe]
+		t.mu.RUnlock()
+		conn, err := t.openConn(ifi)
+		if err == nil && resize {
+			if err := setSendBuffer(conn, size); err != nil {
+				t.reportError(err)
+			}
+		}
+		if err == nil {
+			c.mu.Lock()
+			if c.closed {
+				//the universe was deactivated in the meantime
+				c.mu.Unlock()
+				conn.Close()
+				return
+			}
+			c.conn = conn
+			c.mu.Unlock()
+			if stats, ok := t.universeStats(universe); ok {
+				atomic.AddUint64(&stats.reconnects, 1)
+			}
+			return
+		}
+		t.reportError(err)
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
+// reportError sends the error to the error channel, if one was set. If the channel is not ready,
+// the error is dropped.
+func (t *Transmitter) reportError(err error) {
+	if t.errors == nil {
+		return
+	}
+	select {
+	case t.errors <- err:
+	default:
+	}
+}
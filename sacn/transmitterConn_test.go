@@ -0,0 +1,393 @@
+package sacn
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// failingConn returns errors for the first writes, that are counted in the shared counter
+type failingConn struct {
+	mu       *sync.Mutex
+	failures *int
+	written  *int
+}
+
+func (c *failingConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if *c.failures > 0 {
+		*c.failures--
+		return 0, errors.New("network is unreachable")
+	}
+	*c.written++
+	return len(b), nil
+}
+
+func (c *failingConn) Close() error {
+	return nil
+}
+
+func TestReconnect(t *testing.T) {
+	errs := make(chan error, 10)
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithErrorChannel(errs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu := &sync.Mutex{}
+	failures, written, listened := 2, 0, 0
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		mu.Lock()
+		listened++
+		mu.Unlock()
+		return &failingConn{mu: mu, failures: &failures, written: &written}, nil
+	})
+	trans.keepAliveInterval = 10 * time.Millisecond
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+
+	time.Sleep(300 * time.Millisecond)
+	stats, _ := trans.Stats(1)
+	if stats.SendErrors != 2 || stats.Reconnects != 2 {
+		t.Errorf("Wrong output! Was: %+v; Should've been 2 errors and 2 reconnects", stats)
+	}
+	if stats.PacketsSent == 0 {
+		t.Error("No packets were sent after the reconnection!")
+	}
+	mu.Lock()
+	if listened != 3 {
+		t.Errorf("Wrong output! Was: %v connections; Should've been: %v", listened, 3)
+	}
+	mu.Unlock()
+	if len(errs) != 2 {
+		t.Errorf("Wrong output! Was: %v errors reported; Should've been: %v", len(errs), 2)
+	}
+}
+
+func TestReconnectDoesNotBlockWrites(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the first connection fails every write and all later connections can not be opened
+	mu := &sync.Mutex{}
+	failures, written, opened := 1<<30, 0, 0
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened++
+		if opened > 1 {
+			return nil, errors.New("no network")
+		}
+		return &failingConn{mu: mu, failures: &failures, written: &written}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		select {
+		case ch <- []byte{byte(i)}:
+		case <-time.After(time.Second):
+			t.Fatal("Writing to the channel blocked while reconnecting!")
+		}
+	}
+	close(ch)
+	waitDeactivated(t, &trans, 1)
+}
+
+// blockingConn blocks all writes until the gate is closed
+type blockingConn struct {
+	gate chan struct{}
+}
+
+func (c *blockingConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	<-c.gate
+	return len(b), nil
+}
+
+func (c *blockingConn) Close() error {
+	return nil
+}
+
+func TestActivateBuffered(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.ActivateBuffered(1, 0); err == nil {
+		t.Error("Err was nil! Should have been an error for a buffer size of 0!")
+	}
+	gate := make(chan struct{})
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &blockingConn{gate: gate}, nil
+	})
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	ch, err := trans.ActivateBuffered(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the network is blocked, but writing to the channel must not block
+	for i := 0; i < 10; i++ {
+		select {
+		case ch <- []byte{byte(i)}:
+		case <-time.After(time.Second):
+			t.Fatal("Writing to the buffered channel blocked!")
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if stats, _ := trans.Stats(1); stats.Dropped == 0 {
+		t.Errorf("No dropped frames were counted! Was: %+v", stats)
+	}
+
+	close(gate)
+	close(ch)
+	waitDeactivated(t, &trans, 1)
+}
+
+// recordingSender records all written packets and their destinations
+type recordingSender struct {
+	mu      sync.Mutex
+	packets [][]byte
+	addrs   []string
+}
+
+func (s *recordingSender) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packets = append(s.packets, append([]byte(nil), b...))
+	s.addrs = append(s.addrs, addr.String())
+	return len(b), nil
+}
+
+func (s *recordingSender) Close() error {
+	return nil
+}
+
+func TestSetPacketSenderFactory(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetMulticast(258, true)
+	ch, err := trans.ActivateWithData(258, []byte{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; trans.stats[258].snapshot().PacketsSent == 0; i++ {
+		if i > 100 {
+			t.Fatal("No keep alive packet was sent!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(ch)
+	waitDeactivated(t, &trans, 258)
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	//one keep alive and three packets with the stream terminated flag
+	if len(sender.packets) != 4 {
+		t.Fatalf("Wrong number of packets! Was: %v; Should've been: %v", len(sender.packets), 4)
+	}
+	for _, addr := range sender.addrs {
+		if addr != "239.255.1.2:5568" {
+			t.Errorf("Wrong address! Was: %v; Should've been: %v", addr, "239.255.1.2:5568")
+		}
+	}
+	p, err := NewDataPacketRaw(sender.packets[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Universe() != 258 || !bytes.Equal(p.Data(), []byte{1, 2, 3, 4}) || p.StreamTerminated() {
+		t.Errorf("Wrong output! Was: %v", p)
+	}
+}
+
+// panickingConn panics on every write, like a socket that was closed externally in a broken way
+type panickingConn struct{}
+
+func (c panickingConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	panic("socket is gone")
+}
+
+func (c panickingConn) Close() error {
+	return nil
+}
+
+// interfaceSender records the multicast interface that was set on it
+type interfaceSender struct {
+	recordingSender
+	ifi *net.Interface
+}
+
+func (s *interfaceSender) SetMulticastInterface(ifi *net.Interface) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ifi = ifi
+	return nil
+}
+
+func (s *interfaceSender) multicastInterface() *net.Interface {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ifi
+}
+
+func TestSetPerUniverseMulticastInterface(t *testing.T) {
+	global := &net.Interface{Index: 1, Name: "global"}
+	stage := &net.Interface{Index: 2, Name: "stage"}
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithMulticastInterface(global))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &interfaceSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	if err := trans.SetPerUniverseMulticastInterface(1, stage); err != nil {
+		t.Fatal(err)
+	}
+	ch1, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch1)
+	ch2, err := trans.Activate(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch2)
+
+	sender := func(universe uint16) *interfaceSender {
+		trans.mu.RLock()
+		defer trans.mu.RUnlock()
+		return trans.conns[universe].conn.(*interfaceSender)
+	}
+	if ifi := sender(1).multicastInterface(); ifi != stage {
+		t.Errorf("Wrong interface! Was: %v; Should've been: %v", ifi, stage)
+	}
+	//the global interface is applied by the default factory, so nothing was set
+	if ifi := sender(2).multicastInterface(); ifi != nil {
+		t.Errorf("Wrong interface! Was: %v; Should've been: %v", ifi, nil)
+	}
+
+	//activated universes are changed immediately
+	if err := trans.SetPerUniverseMulticastInterface(2, stage); err != nil {
+		t.Fatal(err)
+	}
+	if ifi := sender(2).multicastInterface(); ifi != stage {
+		t.Errorf("Wrong interface! Was: %v; Should've been: %v", ifi, stage)
+	}
+	if err := trans.SetPerUniverseMulticastInterface(1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ifi := sender(1).multicastInterface(); ifi != global {
+		t.Errorf("Wrong interface! Was: %v; Should've been: %v", ifi, global)
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	errs := make(chan error, 10)
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithErrorChannel(errs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return panickingConn{}, nil
+	})
+	trans.SetMulticast(5, true)
+	ch, err := trans.Activate(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDeactivated(t, &trans, 5)
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "socket is gone") {
+			t.Errorf("Wrong error! Was: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("The panic was not reported on the error channel!")
+	}
+	//writing to the old channel must not block
+	select {
+	case ch <- []byte{1}:
+	case <-time.After(time.Second):
+		t.Error("Writing to the channel of the deactivated universe blocked!")
+	}
+	close(ch)
+
+	//the universe can be activated again
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	if _, err := trans.Activate(5); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		if stats, _ := trans.Stats(5); stats.PacketsSent > 0 {
+			break
+		}
+		if i > 100 {
+			t.Fatal("No packet was sent after reactivating the universe!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSetSendBufferSize(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour
+	if err := trans.SetSendBufferSize(1, 65536); err == nil {
+		t.Error("Setting the send buffer of a not activated universe should fail!")
+	}
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	if err := trans.SetSendBufferSize(1, 0); err == nil {
+		t.Error("Setting a send buffer size of 0 should fail!")
+	}
+	if err := trans.SetSendBufferSize(1, 65536); err != nil {
+		t.Fatal(err)
+	}
+	//the operating system may adjust the size, e.g. Linux doubles it
+	if size, err := trans.GetSendBufferSize(1); err != nil || size < 65536 {
+		t.Errorf("Wrong output! Was: %v, %v; Should've been at least: %v", size, err, 65536)
+	}
+
+	//PacketSenders without a socket are not supported
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	ch2, err := trans.Activate(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch2)
+	if err := trans.SetSendBufferSize(2, 65536); err != ErrNotSupported {
+		t.Errorf("Wrong error! Was: %v; Should've been: %v", err, ErrNotSupported)
+	}
+}
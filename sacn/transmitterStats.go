@@ -0,0 +1,132 @@
+package sacn
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// UniverseStatus holds information about an activated universe of a Transmitter.
+type UniverseStatus struct {
+	Universe     uint16
+	Priority     byte
+	Multicast    bool
+	Destinations []net.UDPAddr
+	KeepAlive    time.Duration
+	PacketsSent  uint64    //the number of packets that were successfully written to the network
+	LastSent     time.Time //zero, if no packet was sent yet
+}
+
+// UniverseStats holds the counters of the network traffic of one universe.
+type UniverseStats struct {
+	PacketsSent uint64    //the number of packets that were successfully written to the network
+	BytesSent   uint64    //the number of bytes that were successfully written to the network
+	SendErrors  uint64    //the number of packets that could not be written to the network
+	Reconnects  uint64    //the number of times the connection was reopened after an error
+	Dropped     uint64    //the number of frames that were dropped, because the buffer was full
+	LastSentAt  time.Time //zero, if no packet was sent yet
+}
+
+// universeStats holds the counters for one universe. The fields are accessed atomically.
+type universeStats struct {
+	packetsSent uint64
+	bytesSent   uint64
+	sendErrors  uint64
+	reconnects  uint64
+	dropped     uint64
+	lastSent    int64 //unix nanoseconds of the last sent packet
+}
+
+// countSend updates the counters after n bytes were written to the network with the given error
+func (s *universeStats) countSend(n int, err error) {
+	if err != nil {
+		atomic.AddUint64(&s.sendErrors, 1)
+		return
+	}
+	atomic.AddUint64(&s.packetsSent, 1)
+	atomic.AddUint64(&s.bytesSent, uint64(n))
+	atomic.StoreInt64(&s.lastSent, time.Now().UnixNano())
+}
+
+// snapshot returns the current values of the counters
+func (s *universeStats) snapshot() UniverseStats {
+	stats := UniverseStats{
+		PacketsSent: atomic.LoadUint64(&s.packetsSent),
+		BytesSent:   atomic.LoadUint64(&s.bytesSent),
+		SendErrors:  atomic.LoadUint64(&s.sendErrors),
+		Reconnects:  atomic.LoadUint64(&s.reconnects),
+		Dropped:     atomic.LoadUint64(&s.dropped),
+	}
+	if last := atomic.LoadInt64(&s.lastSent); last != 0 {
+		stats.LastSentAt = time.Unix(0, last)
+	}
+	return stats
+}
+
+// Stats returns the counters of the given universe. If the universe is not activated,
+// an error is returned.
+func (t *Transmitter) Stats(universe uint16) (UniverseStats, error) {
+	stats, ok := t.universeStats(universe)
+	if !ok {
+		return UniverseStats{}, fmt.Errorf("the given universe %v is not activated", universe)
+	}
+	return stats.snapshot(), nil
+}
+
+// AllStats returns the counters of every activated universe.
+func (t *Transmitter) AllStats() map[uint16]UniverseStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	all := make(map[uint16]UniverseStats)
+	for univ, stats := range t.stats {
+		all[univ] = stats.snapshot()
+	}
+	return all
+}
+
+// GetUniverseStatus returns the status of the given universe. If the universe is not activated,
+// an error is returned.
+func (t *Transmitter) GetUniverseStatus(universe uint16) (UniverseStatus, error) {
+	if !t.IsActivated(universe) {
+		return UniverseStatus{}, fmt.Errorf("the given universe %v is not activated", universe)
+	}
+	return t.universeStatus(universe), nil
+}
+
+// GetAllUniverseStatus returns the status of every activated universe.
+func (t *Transmitter) GetAllUniverseStatus() map[uint16]UniverseStatus {
+	all := make(map[uint16]UniverseStatus)
+	for _, univ := range t.GetActivated() {
+		all[univ] = t.universeStatus(univ)
+	}
+	return all
+}
+
+func (t *Transmitter) universeStatus(universe uint16) UniverseStatus {
+	status := UniverseStatus{
+		Universe:     universe,
+		Multicast:    t.IsMulticast(universe),
+		Destinations: t.Destinations(universe),
+	}
+	t.mu.RLock()
+	status.KeepAlive = t.keepAliveInterval
+	t.mu.RUnlock()
+	t.withMaster(universe, func(p *DataPacket) {
+		status.Priority = p.Priority()
+	})
+	if stats, ok := t.universeStats(universe); ok {
+		snapshot := stats.snapshot()
+		status.PacketsSent = snapshot.PacketsSent
+		status.LastSent = snapshot.LastSentAt
+	}
+	return status
+}
+
+// universeStats returns the counters of the activated universe
+func (t *Transmitter) universeStats(universe uint16) (*universeStats, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stats, ok := t.stats[universe]
+	return stats, ok
+}
@@ -0,0 +1,891 @@
+package sacn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+// listenTestPort opens a udp socket on a random local port to capture packets sent by a transmitter
+func listenTestPort(t *testing.T) (*net.UDPConn, int) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// waitDeactivated waits until the universe is no longer activated on the transmitter
+func waitDeactivated(t *testing.T, trans *Transmitter, universe uint16) {
+	for i := 0; trans.IsActivated(universe); i++ {
+		if i > 100 {
+			t.Fatalf("Universe %v was not deactivated!", universe)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readTestPacket reads the next packet from the given connection and parses it
+func readTestPacket(t *testing.T, conn *net.UDPConn) DataPacket {
+	buf := make([]byte, 638)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewDataPacketRaw(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestActivateWithData(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	data := []byte{1, 2, 3, 4}
+	ch, err := trans.ActivateWithData(1, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+
+	p := readTestPacket(t, conn)
+	if !bytes.Equal(p.Data(), data) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), data)
+	}
+
+	if _, err := trans.ActivateWithData(2, []byte{}); err == nil {
+		t.Error("Err was nil! Should have been an error for empty data!")
+	}
+	if _, err := trans.ActivateWithData(2, make([]byte, 513)); err == nil {
+		t.Error("Err was nil! Should have been an error for too long data!")
+	}
+}
+
+func TestNewTransmitterWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	start := time.Now()
+	if _, err := NewTransmitterWithContext(ctx, "", [16]byte{1}, "test"); err != context.DeadlineExceeded {
+		t.Errorf("Wrong error! Was: %v; Should've been: %v", err, context.DeadlineExceeded)
+	}
+	if took := time.Since(start); took > 100*time.Millisecond {
+		t.Errorf("NewTransmitterWithContext returned after %v", took)
+	}
+
+	if _, err := NewTransmitterWithContext(context.Background(), "127.0.0.1:0", [16]byte{1}, "test"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMulticastOptions(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithMulticastTTL(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := trans.applyMulticastOptions(conn); err != nil {
+		t.Fatal(err)
+	}
+	ttl, err := ipv4.NewPacketConn(conn).MulticastTTL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl != 16 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", ttl, 16)
+	}
+
+	if _, err := NewTransmitter("", [16]byte{1}, "test", WithMulticastTTL(256)); err == nil {
+		t.Error("Err was nil! Should have been an error for an invalid ttl!")
+	}
+}
+
+func TestSetKeepAlive(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trans.keepAliveInterval != 800*time.Millisecond {
+		t.Errorf("Wrong default! Was: %v; Should've been: %v", trans.keepAliveInterval, 800*time.Millisecond)
+	}
+	for _, valid := range []time.Duration{22 * time.Millisecond, 500 * time.Millisecond, 800 * time.Millisecond} {
+		if err := trans.SetKeepAlive(valid); err != nil {
+			t.Errorf("Interval %v: %v", valid, err)
+		}
+		if trans.keepAliveInterval != valid {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", trans.keepAliveInterval, valid)
+		}
+	}
+	for _, invalid := range []time.Duration{-time.Second, 0, 21 * time.Millisecond, 801 * time.Millisecond, time.Second} {
+		if err := trans.SetKeepAlive(invalid); err == nil {
+			t.Errorf("Interval %v did not return an error!", invalid)
+		}
+	}
+	if trans.keepAliveInterval != 800*time.Millisecond {
+		t.Errorf("An invalid interval was applied! Was: %v", trans.keepAliveInterval)
+	}
+}
+
+func TestGetUniverseStatus(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.GetUniverseStatus(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a not activated universe!")
+	}
+	trans.SetPriority(150)
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	readTestPacket(t, conn)
+
+	status, err := trans.GetUniverseStatus(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Universe != 1 || status.Priority != 150 || status.Multicast ||
+		len(status.Destinations) != 1 || status.KeepAlive != keepAliveMax {
+		t.Errorf("Wrong output! Was: %+v", status)
+	}
+	if status.PacketsSent == 0 || status.LastSent.IsZero() {
+		t.Errorf("Sent packets were not counted! Was: %+v", status)
+	}
+	if all := trans.GetAllUniverseStatus(); len(all) != 1 {
+		t.Errorf("Wrong output! Was: %v; Should've been one universe", all)
+	}
+}
+
+func TestStats(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.Stats(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a not activated universe!")
+	}
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	readTestPacket(t, conn)
+	for i := 0; i < 5; i++ {
+		ch <- []byte{byte(i)}
+		readTestPacket(t, conn)
+	}
+
+	//the counter is updated after the packet was written, so wait a short moment
+	var stats UniverseStats
+	for i := 0; i < 100; i++ {
+		stats, _ = trans.Stats(1)
+		if stats.PacketsSent == 6 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if stats.PacketsSent != 6 || stats.SendErrors != 0 || stats.BytesSent != 638+5*128 {
+		t.Errorf("Wrong output! Was: %+v; Should've been 6 packets", stats)
+	}
+	if all := trans.AllStats(); all[1].PacketsSent != 6 {
+		t.Errorf("Wrong output! Was: %v", all)
+	}
+}
+
+func TestMaxFrameRate(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithMaxFrameRate(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTransmitter("", [16]byte{1}, "test", WithMaxFrameRate(-1)); err == nil {
+		t.Error("Err was nil! Should have been an error for a negative frame rate!")
+	}
+	ch := make(chan []byte)
+	sent := make([][]byte, 0)
+	done := make(chan struct{})
+	go func() {
+		trans.receiveFrames(ch, nil, func(data []byte) {
+			sent = append(sent, data)
+		})
+		close(done)
+	}()
+	//send with twice the frame rate for one second
+	for i := 0; i < 40; i++ {
+		ch <- []byte{byte(i)}
+		time.Sleep(25 * time.Millisecond)
+	}
+	close(ch)
+	<-done
+	if len(sent) < 17 || len(sent) > 23 {
+		t.Errorf("Wrong output! Was: %v frames; Should've been about 20", len(sent))
+	}
+	if last := sent[len(sent)-1]; last[0] != 39 {
+		t.Errorf("The most recent frame was not sent! Was: %v; Should've been: %v", last[0], 39)
+	}
+}
+
+func TestPause(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Pause(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a not activated universe!")
+	}
+	trans.keepAliveInterval = 20 * time.Millisecond
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	readTestPacket(t, conn)
+
+	if err := trans.Pause(1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond) //a keep alive that was already running may still arrive
+	before, _ := trans.Stats(1)
+	ch <- []byte{1, 2}
+	time.Sleep(100 * time.Millisecond)
+	after, _ := trans.Stats(1)
+	if after.PacketsSent != before.PacketsSent {
+		t.Errorf("%v packets were sent while paused", after.PacketsSent-before.PacketsSent)
+	}
+
+	trans.Resume(1)
+	time.Sleep(100 * time.Millisecond)
+	if resumed, _ := trans.Stats(1); resumed.PacketsSent == after.PacketsSent {
+		t.Error("No packets were sent after resuming!")
+	}
+}
+
+func TestSendNow(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SendNow(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a not activated universe!")
+	}
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	ch, err := trans.ActivateWithData(1, []byte{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	first := readTestPacket(t, conn)
+
+	start := time.Now()
+	if err := trans.SendNow(1); err != nil {
+		t.Fatal(err)
+	}
+	p := readTestPacket(t, conn)
+	if took := time.Since(start); took > 100*time.Millisecond {
+		t.Errorf("The packet arrived after %v", took)
+	}
+	if !bytes.Equal(p.Data(), []byte{1, 2}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), []byte{1, 2})
+	}
+	if p.Sequence() != first.Sequence()+1 {
+		t.Errorf("Wrong sequence! Was: %v; Should've been: %v", p.Sequence(), first.Sequence()+1)
+	}
+
+	trans.Pause(1)
+	if err := trans.SendNow(1); err == nil {
+		t.Error("Err was nil! Should have been an error for a paused universe!")
+	}
+}
+
+func TestSetUniverse0Broadcast(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	if _, err := trans.Activate(0); err == nil {
+		t.Error("Err was nil! Should have been an error for universe 0!")
+	}
+
+	trans.SetUniverse0Broadcast(true)
+	ch, err := trans.Activate(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(ch)
+	waitDeactivated(t, &trans, 0)
+	sender.mu.Lock()
+	if len(sender.packets) == 0 || sender.addrs[0] != "239.255.0.0:5568" {
+		t.Errorf("Wrong destinations! Was: %v; Should've been: %v", sender.addrs, "239.255.0.0:5568")
+	} else if p, _ := NewDataPacketRaw(sender.packets[0]); p.Universe() != 0 {
+		t.Errorf("Wrong universe! Was: %v; Should've been: %v", p.Universe(), 0)
+	}
+	sender.mu.Unlock()
+
+	trans.SetUniverse0Broadcast(false)
+	if _, err := trans.Activate(0); err == nil {
+		t.Error("Err was nil! Should have been an error for universe 0 after disabling!")
+	}
+}
+
+func TestSetInitialSequenceNumber(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	if err := trans.SetInitialSequenceNumber(1, 250); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	if err := trans.SetInitialSequenceNumber(1, 0); err == nil {
+		t.Error("Err was nil! Should have been an error for an activated universe!")
+	}
+
+	should := []byte{250, 251, 252, 253, 254, 255, 0, 1, 2, 3}
+	for i, seq := range should {
+		if i > 0 {
+			ch <- []byte{byte(i)}
+		}
+		if p := readTestPacket(t, conn); p.Sequence() != seq {
+			t.Errorf("Wrong sequence! Was: %v; Should've been: %v", p.Sequence(), seq)
+		}
+	}
+}
+
+func TestWithPriorityLimiter(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+	if _, err := NewTransmitter("", [16]byte{1}, "test", WithPriorityLimiter(201)); err == nil {
+		t.Error("Err was nil! Should have been an error for an invalid limit!")
+	}
+	trans, err := NewTransmitter("", [16]byte{1}, "test", WithPriorityLimiter(150))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetPriority(200)
+	trans.SetDestinationsWithPort(1, []string{"127.0.0.1"}, port)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	if p := readTestPacket(t, conn); p.Priority() != 150 {
+		t.Errorf("Wrong priority! Was: %v; Should've been: %v", p.Priority(), 150)
+	}
+	//priorities per destination are limited, too
+	if err := trans.SetPerDestinationPriority(1, trans.Destinations(1)[0], 180); err != nil {
+		t.Fatal(err)
+	}
+	ch <- []byte{1}
+	if p := readTestPacket(t, conn); p.Priority() != 150 {
+		t.Errorf("Wrong priority! Was: %v; Should've been: %v", p.Priority(), 150)
+	}
+}
+
+func TestSetDestinationsWithPort(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := trans.SetDestinations(1, []string{"127.0.0.1", "not an ip", "", "192.168.1.1", "1.2.3.4.5"})
+	if len(errs) != 2 {
+		t.Fatalf("Wrong output! Was: %v; Should've been 2 errors", errs)
+	}
+	if e, ok := errs[0].(*DestinationError); !ok || e.Index != 1 || e.Destination != "not an ip" {
+		t.Errorf("Wrong error! Was: %v", errs[0])
+	}
+	if e, ok := errs[1].(*DestinationError); !ok || e.Index != 4 {
+		t.Errorf("Wrong error! Was: %v", errs[1])
+	}
+	if dests := trans.Destinations(1); len(dests) != 2 || dests[0].Port != 5568 {
+		t.Errorf("Wrong output! Was: %v", dests)
+	}
+
+	//send to a non standard port
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+	if errs := trans.SetDestinationsWithPort(2, []string{"127.0.0.1"}, port); errs != nil {
+		t.Fatal(errs)
+	}
+	ch, err := trans.Activate(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	if p := readTestPacket(t, conn); p.Universe() != 2 {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Universe(), 2)
+	}
+}
+
+func TestSetRawDestinations(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dests := []net.UDPAddr{{IP: net.IPv4(192, 168, 1, 1), Port: 5568}, {IP: net.IPv4(10, 0, 0, 1), Port: 6000}}
+	trans.SetRawDestinations(1, dests)
+	dests[0].Port = 1 //must not affect the transmitter
+	out := trans.Destinations(1)
+	if len(out) != 2 || out[0].Port != 5568 || !out[1].IP.Equal(net.IPv4(10, 0, 0, 1)) || out[1].Port != 6000 {
+		t.Errorf("Wrong output! Was: %v", out)
+	}
+
+	if err := trans.AddRawDestination(1, net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 5568}); err != nil {
+		t.Error(err)
+	}
+	if err := trans.AddRawDestination(1, net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 5568}); err == nil {
+		t.Error("Err was nil! Should have been an error for a duplicate destination!")
+	}
+	if err := trans.AddRawDestination(1, net.UDPAddr{Port: 5568}); err == nil {
+		t.Error("Err was nil! Should have been an error for a missing ip-address!")
+	}
+	if out := trans.Destinations(1); len(out) != 3 {
+		t.Errorf("Wrong output! Was: %v; Should've been 3 destinations", out)
+	}
+}
+
+func TestSetUniverseSourceName(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "global")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SetUniverseSourceName(1, string(make([]byte, 64))); err == nil {
+		t.Error("Err was nil! Should have been an error for a too long source name!")
+	}
+	trans.keepAliveInterval = time.Hour
+	trans.SetUniverseSourceName(1, "first")
+	trans.SetUniverseSourceName(2, "second")
+	names := map[uint16]string{1: "first", 2: "second", 3: "global"}
+	for univ := range names {
+		trans.SetDestinationsWithPort(univ, []string{"127.0.0.1"}, port)
+		ch, err := trans.Activate(univ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer close(ch)
+	}
+	for range names {
+		p := readTestPacket(t, conn)
+		if p.SourceName() != names[p.Universe()] {
+			t.Errorf("Wrong output! Was: %v; Should've been: %v", p.SourceName(), names[p.Universe()])
+		}
+	}
+}
+
+func TestSetOnDataChange(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type change struct{ old, new []byte }
+	changes := make(chan change, 10)
+	trans.SetOnDataChange(1, func(old, new []byte) {
+		changes <- change{old, new}
+	})
+	ch, err := trans.ActivateWithData(1, []byte{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch <- []byte{1, 2}
+	ch <- []byte{1, 2}
+	ch <- []byte{1, 3}
+	close(ch)
+
+	c := <-changes
+	if !bytes.Equal(c.old, []byte{1, 2}) || !bytes.Equal(c.new, []byte{1, 3}) {
+		t.Errorf("Wrong output! Was: %v -> %v; Should've been: %v -> %v", c.old, c.new, []byte{1, 2}, []byte{1, 3})
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(changes) != 0 {
+		t.Errorf("The callback was called %v times for unchanged data", len(changes))
+	}
+}
+
+func TestSetPacketInterceptor(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int32
+	trans.SetPacketInterceptor(func(p *DataPacket) bool {
+		return atomic.AddInt32(&calls, 1)%2 == 1 //suppress every second packet
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetRawDestinations(1, []net.UDPAddr{{IP: net.IPv4(127, 0, 0, 1), Port: port}})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	for i := 1; i < 10; i++ {
+		ch <- []byte{byte(i)}
+	}
+
+	received := 0
+	buf := make([]byte, 638)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		if _, _, err := conn.ReadFromUDP(buf); err != nil {
+			break
+		}
+		received++
+	}
+	if c := atomic.LoadInt32(&calls); c != 10 {
+		t.Errorf("Wrong number of calls! Was: %v; Should've been: %v", c, 10)
+	}
+	if received != 5 {
+		t.Errorf("Wrong number of packets! Was: %v; Should've been: %v", received, 5)
+	}
+}
+
+func TestSetPerDestinationPriority(t *testing.T) {
+	primary, primaryPort := listenTestPort(t)
+	defer primary.Close()
+	backup, backupPort := listenTestPort(t)
+	defer backup.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primaryAddr := net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: primaryPort}
+	backupAddr := net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: backupPort}
+	trans.SetRawDestinations(1, []net.UDPAddr{primaryAddr, backupAddr})
+	if err := trans.SetPerDestinationPriority(1, primaryAddr, 200); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SetPerDestinationPriority(1, primaryAddr, 201); err == nil {
+		t.Error("A priority of 201 should not be accepted!")
+	}
+	if err := trans.SetPerDestinationPriority(1, net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 1}, 200); err == nil {
+		t.Error("A destination that is not set should not be accepted!")
+	}
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+
+	if p := readTestPacket(t, primary); p.Priority() != 200 {
+		t.Errorf("Wrong priority! Was: %v; Should've been: %v", p.Priority(), 200)
+	}
+	if p := readTestPacket(t, backup); p.Priority() != 100 {
+		t.Errorf("Wrong priority! Was: %v; Should've been: %v", p.Priority(), 100)
+	}
+
+	//a removed destination loses its priority, also if it is added again later
+	trans.SetRawDestinations(1, []net.UDPAddr{backupAddr})
+	trans.SetRawDestinations(1, []net.UDPAddr{primaryAddr, backupAddr})
+	if _, prios := trans.destinationSnapshot(1); len(prios) != 0 {
+		t.Errorf("Wrong output! Was: %v; Should've been no priorities", prios)
+	}
+}
+
+func TestSetUniverseCID(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.SetUniverseCID(2, [16]byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	cids := map[uint16][16]byte{1: {1}, 2: {2}}
+	for univ := range cids {
+		trans.SetDestinationsWithPort(univ, []string{"127.0.0.1"}, port)
+		ch, err := trans.Activate(univ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer close(ch)
+	}
+	if err := trans.SetUniverseCID(1, [16]byte{3}); err == nil {
+		t.Error("Setting the CID of an activated universe should fail!")
+	}
+
+	for i := 0; i < 2; i++ {
+		p := readTestPacket(t, conn)
+		if p.CID() != cids[p.Universe()] {
+			t.Errorf("Wrong CID for universe %v! Was: %v; Should've been: %v", p.Universe(), p.CID(), cids[p.Universe()])
+		}
+	}
+}
+
+func TestDestinationsConcurrent(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetDestinations(1, []string{"127.0.0.1"})
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer waitDeactivated(t, &trans, 1)
+	defer close(ch)
+	for i := 0; trans.stats[1].snapshot().PacketsSent == 0; i++ {
+		if i > 100 {
+			t.Fatal("No keep alive packet was sent!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	conn := trans.conns[1]
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 300; i++ {
+			trans.SetDestinations(1, []string{"127.0.0.1", "127.0.0.2"})
+			trans.AddRawDestination(1, net.UDPAddr{IP: net.IPv4(127, 0, 0, 3), Port: 5568})
+			trans.Destinations(1)
+		}
+		close(done)
+	}()
+	for i := 0; i < 300; i++ {
+		trans.sendOut(conn, 1)
+	}
+	<-done
+	if dests := trans.Destinations(1); len(dests) != 3 {
+		t.Errorf("Wrong number of destinations! Was: %v; Should've been: %v", len(dests), 3)
+	}
+}
+
+func TestActivateConcurrent(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	//every goroutine tries to activate all universes, so every universe is contended
+	var wg sync.WaitGroup
+	var activated int32
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for univ := uint16(1); univ <= 100; univ++ {
+				if _, err := trans.Activate(univ); err == nil {
+					atomic.AddInt32(&activated, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if activated != 100 {
+		t.Errorf("Wrong number of activations! Was: %v; Should've been: %v", activated, 100)
+	}
+	if list := trans.GetActivated(); len(list) != 100 {
+		t.Errorf("Wrong number of activated universes! Was: %v; Should've been: %v", len(list), 100)
+	}
+}
+
+func TestActivateMany(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	channels, err := trans.ActivateMany([]uint16{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 3 {
+		t.Errorf("Wrong number of channels! Was: %v; Should've been: %v", len(channels), 3)
+	}
+	for _, univ := range []uint16{1, 2, 3} {
+		if ch, ok := trans.Channel(univ); !ok || ch != channels[univ] {
+			t.Errorf("Wrong channel for universe %v!", univ)
+		}
+	}
+
+	//universe 3 is already activated, so universe 4 must not stay activated
+	if _, err := trans.ActivateMany([]uint16{4, 3}); err == nil {
+		t.Error("Activating an activated universe did not return an error!")
+	}
+	waitDeactivated(t, &trans, 4)
+}
+
+func TestDisableZeroSend(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return sender, nil
+	})
+	trans.keepAliveInterval = 20 * time.Millisecond
+	trans.SetMulticast(1, true)
+	trans.DisableZeroSend(1, true)
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+	sent := func() int {
+		stats, _ := trans.Stats(1)
+		return int(stats.PacketsSent)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := sent(); n != 0 {
+		t.Errorf("Keep alive packets with all slots 0 were sent! Was: %v; Should've been: %v", n, 0)
+	}
+	//data from the channel is always sent
+	ch <- make([]byte, 512)
+	for i := 0; sent() == 0; i++ {
+		if i > 100 {
+			t.Fatal("The data from the channel was not sent!")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	//a single slot that is not 0 enables the keep alive packets
+	ch <- []byte{0, 1}
+	before := sent()
+	time.Sleep(100 * time.Millisecond)
+	if n := sent(); n < before+2 {
+		t.Errorf("Wrong number of keep alive packets! Was: %v; Should've been at least: %v", n-before, 2)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	if err := trans.SetKeepAlive(keepAliveMin); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	//every goroutine drives its own universe, while changing the shared settings
+	for univ := uint16(1); univ <= 8; univ++ {
+		wg.Add(1)
+		go func(univ uint16) {
+			defer wg.Done()
+			trans.SetDestinations(univ, []string{"127.0.0.1"})
+			trans.SetMulticast(univ, univ%2 == 0)
+			ch, err := trans.Activate(univ)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for i := 0; i < 50; i++ {
+				ch <- []byte{byte(i)}
+				trans.SetPriority(byte(100 + i))
+				trans.SetUniverseSourceName(univ, fmt.Sprintf("universe %v", i))
+				trans.SetOnDataChange(univ, func(old, new []byte) {})
+				trans.GetAllUniverseStatus()
+				trans.ExportConfig()
+			}
+			close(ch)
+		}(univ)
+	}
+	wg.Wait()
+	for univ := uint16(1); univ <= 8; univ++ {
+		waitDeactivated(t, &trans, univ)
+	}
+}
+
+func TestDeactivate(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.keepAliveInterval = time.Hour
+	if err := trans.Deactivate(1); err == nil {
+		t.Error("Deactivating a universe that is not activated did not return an error!")
+	}
+	ch, err := trans.Activate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Deactivate(1); err != nil {
+		t.Fatal(err)
+	}
+	if trans.IsActivated(1) {
+		t.Error("The universe is still activated!")
+	}
+	//the channel still belongs to the caller
+	select {
+	case ch <- []byte{1}:
+	case <-time.After(time.Second):
+		t.Error("Writing to the channel of the deactivated universe blocked!")
+	}
+	close(ch)
+	if err := trans.Deactivate(1); err == nil {
+		t.Error("Deactivating a universe twice did not return an error!")
+	}
+}
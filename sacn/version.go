@@ -0,0 +1,61 @@
+package sacn
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SpecVersion is the version of the E1.31 specification that is implemented by this package.
+const SpecVersion = "ANSI E1.31-2016"
+
+// LibraryVersion is the semantic version of this package. It has to be updated with every release.
+const LibraryVersion = "0.1.0"
+
+// Version returns the version of this package together with the implemented E1.31 version, e.g. for
+// the logs of certification tests.
+func Version() string {
+	return fmt.Sprintf("go-sacn %v (%v)", LibraryVersion, SpecVersion)
+}
+
+// CheckSpecCompliance audits the configuration and the activated universes of the transmitter for obvious
+// violations of the E1.31 specification and returns a human-readable description of every violation.
+// This does not check the packets on the network. An empty slice is returned if nothing was found.
+func CheckSpecCompliance(tx *Transmitter) []string {
+	violations := make([]string, 0)
+	tx.mu.RLock()
+	priority, keepAlive := tx.priority, tx.keepAliveInterval
+	names := make(map[uint16]string, len(tx.sourceNames))
+	for univ, name := range tx.sourceNames {
+		names[univ] = name
+	}
+	tx.mu.RUnlock()
+	if tx.cid == [16]byte{} {
+		violations = append(violations, "the CID is the nil UUID, but has to be unique for every source (E1.31 5.6)")
+	}
+	if len(tx.sourceName) > 63 {
+		violations = append(violations, fmt.Sprintf("the source name is %v bytes long, but at most 63 bytes are allowed (E1.31 6.2.2)", len(tx.sourceName)))
+	}
+	if priority > 200 {
+		violations = append(violations, fmt.Sprintf("the priority is %v, but has to be in range [0-200] (E1.31 6.2.3)", priority))
+	}
+	if keepAlive > keepAliveMax {
+		violations = append(violations, fmt.Sprintf("the keep alive interval is %v, but data has to be sent at least every %v (E1.31 6.6.1)", keepAlive, keepAliveMax))
+	}
+	for _, universe := range sortedUniverses(tx.GetActivated()) {
+		if !IsValidDataUniverse(universe) {
+			violations = append(violations, fmt.Sprintf("universe %v is activated, but only universes in range [1-63999] are allowed (E1.31 6.2.7)", universe))
+		}
+		if name, ok := names[universe]; ok && len(name) > 63 {
+			violations = append(violations, fmt.Sprintf("the source name of universe %v is %v bytes long, but at most 63 bytes are allowed (E1.31 6.2.2)", universe, len(name)))
+		}
+	}
+	return violations
+}
+
+// sortedUniverses sorts the universes in ascending order
+func sortedUniverses(universes []uint16) []uint16 {
+	sort.Slice(universes, func(i, j int) bool {
+		return universes[i] < universes[j]
+	})
+	return universes
+}
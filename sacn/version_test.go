@@ -0,0 +1,53 @@
+package sacn
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVersion(t *testing.T) {
+	if v := Version(); !strings.Contains(v, SpecVersion) || !strings.Contains(v, LibraryVersion) {
+		t.Errorf("Wrong output! Was: %v; Should contain: %v and %v", v, SpecVersion, LibraryVersion)
+	}
+}
+
+func TestCheckSpecCompliance(t *testing.T) {
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if violations := CheckSpecCompliance(&trans); len(violations) != 0 {
+		t.Errorf("A compliant transmitter had violations: %v", violations)
+	}
+
+	trans, err = NewTransmitter("", [16]byte{}, strings.Repeat("a", 70))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.SetPacketSenderFactory(func(bind string) (PacketSender, error) {
+		return &recordingSender{}, nil
+	})
+	trans.SetPriority(250)
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetUniverse0Broadcast(true)
+	ch, err := trans.Activate(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(ch)
+
+	violations := CheckSpecCompliance(&trans)
+	for _, should := range []string{"CID", "source name", "priority", "keep alive", "universe 0"} {
+		found := false
+		for _, violation := range violations {
+			found = found || strings.Contains(violation, should)
+		}
+		if !found {
+			t.Errorf("The violation %q was not reported: %v", should, violations)
+		}
+	}
+	if len(violations) != 5 {
+		t.Errorf("Wrong number of violations! Was: %v; Should've been: %v", len(violations), 5)
+	}
+}
@@ -0,0 +1,89 @@
+package sacn
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WatchdogAction is the action of a watchdog, if no new data was written to a universe within the timeout.
+type WatchdogAction int
+
+const (
+	// WatchdogBlackout sends a frame with 512 zeros.
+	WatchdogBlackout WatchdogAction = iota
+	// WatchdogHold keeps the current data. The keep alive packets continue to send it.
+	WatchdogHold
+)
+
+// watchdog holds the configuration of the watchdog of one universe
+type watchdog struct {
+	timeout    time.Duration
+	action     WatchdogAction
+	lastUpdate int64 //unix nanoseconds of the last data written by the user. Accessed atomically
+	stop       chan struct{}
+}
+
+func (w *watchdog) touch() {
+	atomic.StoreInt64(&w.lastUpdate, time.Now().UnixNano())
+}
+
+// SetWatchdog sets a watchdog for the universe, that acts like a dead man's switch: if no new data was
+// written to the channel of the universe for the timeout, the action is executed once. The watchdog is
+// reset by every frame that is written to the channel. The watchdog stays set, if the universe is
+// deactivated and activated again. A timeout of 0 removes the watchdog.
+func (t *Transmitter) SetWatchdog(universe uint16, timeout time.Duration, action WatchdogAction) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.watchdogs[universe]; ok {
+		close(old.stop)
+		delete(t.watchdogs, universe)
+	}
+	if timeout <= 0 {
+		return
+	}
+	w := &watchdog{
+		timeout: timeout,
+		action:  action,
+		stop:    make(chan struct{}),
+	}
+	w.touch()
+	t.watchdogs[universe] = w
+	if conn, ok := t.conns[universe]; ok {
+		go t.runWatchdog(universe, w, conn)
+	}
+}
+
+// runWatchdog checks the watchdog until it is removed or the universe is deactivated.
+// The connection identifies the activation of the universe the watchdog was started for.
+func (t *Transmitter) runWatchdog(universe uint16, w *watchdog, conn *universeConn) {
+	var triggered int64 //the last update for which the action was executed
+	wait := w.timeout
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-time.After(wait):
+		}
+		if !t.isCurrentConn(universe, conn) {
+			return //the universe was deactivated
+		}
+		last := atomic.LoadInt64(&w.lastUpdate)
+		elapsed := time.Since(time.Unix(0, last))
+		if elapsed < w.timeout {
+			wait = w.timeout - elapsed
+			continue
+		}
+		wait = w.timeout
+		if last == triggered {
+			continue
+		}
+		triggered = last
+		if w.action == WatchdogBlackout {
+			if t.withMaster(universe, func(p *DataPacket) {
+				p.SetData(make([]byte, 512))
+			}) {
+				t.sendOut(conn, universe)
+			}
+		}
+	}
+}
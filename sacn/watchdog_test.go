@@ -0,0 +1,46 @@
+package sacn
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	conn, port := listenTestPort(t)
+	defer conn.Close()
+
+	trans, err := NewTransmitter("", [16]byte{1}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans.keepAliveInterval = time.Hour //only the first keep alive packet is sent
+	trans.SetRawDestinations(1, []net.UDPAddr{{IP: net.IPv4(127, 0, 0, 1), Port: port}})
+	trans.SetWatchdog(1, 50*time.Millisecond, WatchdogBlackout)
+	trans.SetWatchdog(2, 50*time.Millisecond, WatchdogHold)
+	for _, univ := range []uint16{1, 2} {
+		ch, err := trans.ActivateWithData(univ, []byte{5, 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer close(ch)
+	}
+
+	if p := readTestPacket(t, conn); !bytes.Equal(p.Data(), []byte{5, 5}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), []byte{5, 5})
+	}
+	start := time.Now()
+	p := readTestPacket(t, conn)
+	if shouldBe := make([]byte, 512); !bytes.Equal(p.Data(), shouldBe) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", p.Data(), shouldBe)
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Error("The watchdog triggered too early!")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if data := trans.master[2].Data(); !bytes.Equal(data, []byte{5, 5}) {
+		t.Errorf("Wrong output! Was: %v; Should've been: %v", data, []byte{5, 5})
+	}
+}